@@ -8,7 +8,6 @@ import (
 	"net/http/httptest"
 
 	"github.com/jeanmolossi/maigo/examples/testserver"
-	"github.com/jeanmolossi/maigo/pkg/httpx"
 	"github.com/jeanmolossi/maigo/pkg/httpx/metrics"
 	"github.com/jeanmolossi/maigo/pkg/maigo"
 	"github.com/prometheus/client_golang/prometheus"
@@ -27,11 +26,8 @@ func main() {
 		Subsystem:  "client",
 	})
 
-	transport := httpx.Compose(http.DefaultTransport, metricsChain)
-
 	client := maigo.NewClient(ts.URL).
-		Config().
-		SetCustomTransport(transport).
+		Use(metricsChain).
 		Build()
 
 	_, err := client.GET("/users").Send()
@@ -11,7 +11,6 @@ import (
 	"time"
 
 	"github.com/jeanmolossi/maigo/examples/testserver"
-	"github.com/jeanmolossi/maigo/pkg/httpx"
 	"github.com/jeanmolossi/maigo/pkg/httpx/tracing"
 	"github.com/jeanmolossi/maigo/pkg/maigo"
 	"go.opentelemetry.io/otel"
@@ -96,13 +95,8 @@ func main() {
 
 	tr := otel.Tracer("examples/request_with_tracing")
 
-	transport := httpx.Compose(
-		http.DefaultTransport,
-		tracing.WithTracing(),
-	)
-
 	client := maigo.NewClient(ts.URL).
-		Config().SetCustomTransport(transport).
+		Use(tracing.WithTracing()).
 		Build()
 
 	err = func(ctx context.Context) error {
@@ -0,0 +1,140 @@
+package httpx
+
+import (
+	"net/http"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync/atomic"
+)
+
+// Compile time check if [Chain] implements [http.RoundTripper].
+var _ http.RoundTripper = (*Chain)(nil)
+
+// Middleware is a [ChainedRoundTripper] with a name, so a [Chain] can be
+// inspected once composed.
+type Middleware struct {
+	Name string
+	Wrap ChainedRoundTripper
+}
+
+// Named attaches name to wrap.
+func Named(name string, wrap ChainedRoundTripper) Middleware {
+	return Middleware{Name: name, Wrap: wrap}
+}
+
+// Chain is a round tripper composed of named middlewares layered over a base
+// round tripper. The base can be replaced at any time without losing the
+// middlewares, so transport settings and middlewares can be configured in
+// any order.
+//
+// Chain is meant to be configured before it is used: Use and SetBase must not
+// be called concurrently with RoundTrip.
+type Chain struct {
+	base        http.RoundTripper
+	middlewares []Middleware
+	composed    atomic.Pointer[http.RoundTripper]
+}
+
+// NewChain creates a chain over base. A nil base falls back to
+// [http.DefaultTransport] when the chain is used.
+func NewChain(base http.RoundTripper, middlewares ...Middleware) *Chain {
+	c := &Chain{base: base}
+	c.Use(middlewares...)
+
+	return c
+}
+
+// Use appends middlewares to the chain. The request goes down through them in
+// the order they were added, the first one being the outermost.
+func (c *Chain) Use(middlewares ...Middleware) *Chain {
+	for _, m := range middlewares {
+		if m.Wrap == nil {
+			continue
+		}
+
+		if m.Name == "" {
+			m.Name = nameOf(m.Wrap)
+		}
+
+		c.middlewares = append(c.middlewares, m)
+	}
+
+	c.composed.Store(nil)
+
+	return c
+}
+
+// Base returns the round tripper the middlewares are layered over. It may be
+// nil, meaning [http.DefaultTransport].
+func (c *Chain) Base() http.RoundTripper {
+	return c.base
+}
+
+// SetBase replaces the round tripper the middlewares are layered over.
+func (c *Chain) SetBase(base http.RoundTripper) {
+	c.base = base
+	c.composed.Store(nil)
+}
+
+// Names returns the middleware names from the outermost to the innermost.
+func (c *Chain) Names() []string {
+	names := make([]string, len(c.middlewares))
+	for i, m := range c.middlewares {
+		names[i] = m.Name
+	}
+
+	return names
+}
+
+// Middlewares returns a copy of the middlewares in the chain.
+func (c *Chain) Middlewares() []Middleware {
+	return append([]Middleware(nil), c.middlewares...)
+}
+
+// RoundTrip implements [http.RoundTripper].
+func (c *Chain) RoundTrip(r *http.Request) (*http.Response, error) {
+	rt := c.composed.Load()
+	if rt == nil {
+		chain := make([]ChainedRoundTripper, len(c.middlewares))
+		for i, m := range c.middlewares {
+			chain[i] = m.Wrap
+		}
+
+		composed := Compose(c.base, chain...)
+		rt = &composed
+		c.composed.Store(rt)
+	}
+
+	return (*rt).RoundTrip(r)
+}
+
+// nameOf derives a middleware name from the function that built it, e.g.
+// "retry.WithRetry" for the closure returned by retry.WithRetry.
+func nameOf(wrap ChainedRoundTripper) string {
+	fn := runtime.FuncForPC(reflect.ValueOf(wrap).Pointer())
+	if fn == nil {
+		return "anonymous"
+	}
+
+	name := fn.Name()
+
+	// drop the import path, keeping package.Function
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+
+	// drop closure suffixes such as .func1 or .func1.2
+	parts := strings.Split(name, ".")
+	for len(parts) > 2 && isClosureSuffix(parts[len(parts)-1]) {
+		parts = parts[:len(parts)-1]
+	}
+
+	return strings.Join(parts, ".")
+}
+
+func isClosureSuffix(part string) bool {
+	part = strings.TrimPrefix(part, "func")
+
+	return strings.Trim(part, "0123456789") == ""
+}
@@ -0,0 +1,58 @@
+package httpx
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func tagging(tag string) ChainedRoundTripper {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFn(func(r *http.Request) (*http.Response, error) {
+			r.Header.Add("X-Chain", tag)
+			return next.RoundTrip(r)
+		})
+	}
+}
+
+func TestChain_OrderAndNames(t *testing.T) {
+	var seen []string
+
+	base := RoundTripperFn(func(r *http.Request) (*http.Response, error) {
+		seen = r.Header.Values("X-Chain")
+		return NewResp(200, ""), nil
+	})
+
+	chain := NewChain(base, Named("a", tagging("a")))
+	chain.Use(Middleware{Wrap: tagging("b")})
+
+	require.Equal(t, []string{"a", "httpx.tagging"}, chain.Names())
+
+	req, _ := http.NewRequest(http.MethodGet, "http://x", nil)
+	_, err := chain.RoundTrip(req)
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "b"}, seen)
+}
+
+func TestChain_SetBaseKeepsMiddlewares(t *testing.T) {
+	first, firstAssert := NewRoundTripMockBuilder().Build(t)
+	second, secondAssert := NewRoundTripMockBuilder().Build(t)
+
+	chain := NewChain(first, Named("tag", tagging("tag")))
+
+	req, _ := http.NewRequest(http.MethodGet, "http://x", nil)
+	_, err := chain.RoundTrip(req)
+	require.NoError(t, err)
+
+	chain.SetBase(second)
+
+	req, _ = http.NewRequest(http.MethodGet, "http://x", nil)
+	_, err = chain.RoundTrip(req)
+	require.NoError(t, err)
+
+	firstAssert.Calls(1)
+	secondAssert.Calls(1)
+	secondAssert.SeenHeaders(0, "X-Chain", "tag")
+	require.Equal(t, second, chain.Base())
+}
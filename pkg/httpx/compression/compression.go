@@ -0,0 +1,174 @@
+package compression
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+)
+
+// Encoding is an HTTP content coding, as sent in the Content-Encoding header.
+type Encoding string
+
+const (
+	Gzip Encoding = "gzip"
+	// Deflate is the zlib format, as defined for HTTP by RFC 9110.
+	Deflate Encoding = "deflate"
+	Zstd    Encoding = "zstd"
+	Brotli  Encoding = "br"
+)
+
+// zstd frames choose their own window, so the decoder bounds what a response
+// can make it allocate. RFC 8878 asks HTTP decoders to accept windows of at
+// least 8MiB.
+const (
+	zstdMaxWindow = 8 << 20  // 8MiB
+	zstdMaxMemory = 64 << 20 // 64MiB
+)
+
+// ErrUnsupportedEncoding is returned for content codings this package does
+// not implement.
+var ErrUnsupportedEncoding = errors.New("unsupported content encoding")
+
+// Supported reports whether the encoding is implemented by this package.
+func (e Encoding) Supported() bool {
+	switch e {
+	case Gzip, Deflate, Zstd, Brotli:
+		return true
+	default:
+		return false
+	}
+}
+
+// String returns the encoding as sent in the Content-Encoding header.
+func (e Encoding) String() string {
+	return string(e)
+}
+
+// NewWriter returns a writer compressing into w with the encoding. Closing
+// it flushes the compressed stream but does not close w.
+func NewWriter(w io.Writer, encoding Encoding) (io.WriteCloser, error) {
+	switch encoding {
+	case Gzip:
+		return gzip.NewWriter(w), nil
+	case Deflate:
+		return zlib.NewWriter(w), nil
+	case Zstd:
+		return zstd.NewWriter(w)
+	case Brotli:
+		return brotli.NewWriter(w), nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedEncoding, encoding)
+	}
+}
+
+// NewReader returns a reader decompressing r with the encoding. Closing it
+// releases the decompressor but does not close r. Deflate accepts both the
+// zlib format and the raw deflate streams some servers send instead.
+func NewReader(r io.Reader, encoding Encoding) (io.ReadCloser, error) {
+	switch encoding {
+	case Gzip:
+		return gzip.NewReader(r)
+	case Deflate:
+		return newDeflateReader(r)
+	case Zstd:
+		decoder, err := zstd.NewReader(r,
+			zstd.WithDecoderMaxWindow(zstdMaxWindow),
+			zstd.WithDecoderMaxMemory(zstdMaxMemory),
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		return decoder.IOReadCloser(), nil
+	case Brotli:
+		return io.NopCloser(brotli.NewReader(r)), nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedEncoding, encoding)
+	}
+}
+
+func newDeflateReader(r io.Reader) (io.ReadCloser, error) {
+	buffered := bufio.NewReader(r)
+
+	// a zlib header is a deflate method nibble and a checksum multiple of 31
+	header, err := buffered.Peek(2)
+	if err == nil && header[0]&0x0f == 8 && (uint16(header[0])<<8|uint16(header[1]))%31 == 0 {
+		return zlib.NewReader(buffered)
+	}
+
+	return flate.NewReader(buffered), nil
+}
+
+// Compress returns data compressed with the encoding.
+func Compress(data []byte, encoding Encoding) ([]byte, error) {
+	var buf bytes.Buffer
+
+	writer, err := NewWriter(&buf, encoding)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := writer.Write(data); err != nil {
+		return nil, err
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// CompressReader streams what r produces compressed with the encoding. The
+// returned reader must be closed to release r, which it closes when it is
+// an io.Closer.
+func CompressReader(r io.Reader, encoding Encoding) (io.ReadCloser, error) {
+	if !encoding.Supported() {
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedEncoding, encoding)
+	}
+
+	reader, writer := io.Pipe()
+
+	go func() {
+		writer.CloseWithError(compressTo(writer, r, encoding))
+	}()
+
+	return &compressedReader{PipeReader: reader, source: r}, nil
+}
+
+func compressTo(w io.Writer, r io.Reader, encoding Encoding) error {
+	compressor, err := NewWriter(w, encoding)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(compressor, r); err != nil {
+		_ = compressor.Close()
+		return err
+	}
+
+	return compressor.Close()
+}
+
+type compressedReader struct {
+	*io.PipeReader
+	source io.Reader
+}
+
+func (c *compressedReader) Close() error {
+	err := c.PipeReader.Close()
+
+	if closer, ok := c.source.(io.Closer); ok {
+		err = errors.Join(err, closer.Close())
+	}
+
+	return err
+}
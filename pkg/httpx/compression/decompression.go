@@ -0,0 +1,183 @@
+package compression
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/jeanmolossi/maigo/pkg/httpx"
+)
+
+// DecompressionConfig contains settings for the decompression round tripper.
+type DecompressionConfig struct {
+	// Encodings are advertised in the Accept-Encoding header, in order of
+	// preference. Defaults to gzip, deflate, br and zstd.
+	Encodings []Encoding
+}
+
+// WithDecompression wraps the next RoundTripper advertising the supported
+// encodings through Accept-Encoding, unless the request already defines it,
+// and decoding the responses sent with any supported Content-Encoding, even
+// when the Accept-Encoding was set by hand. Decoded responses lose their
+// Content-Encoding and Content-Length headers; OriginalEncoding reports the
+// encoding they were sent with.
+func WithDecompression(cfg DecompressionConfig) httpx.ChainedRoundTripper {
+	if len(cfg.Encodings) == 0 {
+		cfg.Encodings = []Encoding{Gzip, Deflate, Brotli, Zstd}
+	}
+
+	accept := make([]string, len(cfg.Encodings))
+	for i, encoding := range cfg.Encodings {
+		accept[i] = encoding.String()
+	}
+
+	acceptEncoding := strings.Join(accept, ", ")
+
+	return func(next http.RoundTripper) http.RoundTripper {
+		return httpx.RoundTripperFn(func(r *http.Request) (*http.Response, error) {
+			if r.Header.Get("Accept-Encoding") == "" {
+				// the caller request must not be modified
+				r = r.Clone(r.Context())
+				r.Header.Set("Accept-Encoding", acceptEncoding)
+			}
+
+			resp, err := next.RoundTrip(r)
+			if err != nil {
+				return nil, err
+			}
+
+			decompress(resp)
+
+			return resp, nil
+		})
+	}
+}
+
+// decompress replaces the body of resp with a reader decoding it, unless it
+// has no body or an encoding is not supported.
+func decompress(resp *http.Response) {
+	original := resp.Header.Get("Content-Encoding")
+
+	encodings := parseEncodings(original)
+	if len(encodings) == 0 || !responseHasBody(resp) {
+		return
+	}
+
+	for _, encoding := range encodings {
+		if !encoding.Supported() {
+			return
+		}
+	}
+
+	resp.Body = &decodingReader{body: resp.Body, encodings: encodings, original: original}
+	resp.ContentLength = -1
+	resp.Uncompressed = true
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+}
+
+// OriginalEncoding returns the Content-Encoding resp was sent with when
+// WithDecompression decoded its body, or an empty string otherwise. Bodies
+// wrapping the decoded one are looked through when they have an
+// Unwrap() io.ReadCloser method.
+func OriginalEncoding(resp *http.Response) string {
+	body := resp.Body
+
+	for body != nil {
+		switch b := body.(type) {
+		case *decodingReader:
+			return b.original
+		case interface{ Unwrap() io.ReadCloser }:
+			body = b.Unwrap()
+		default:
+			return ""
+		}
+	}
+
+	return ""
+}
+
+// parseEncodings returns the encodings of a Content-Encoding header in the
+// order they were applied, ignoring identity.
+func parseEncodings(value string) []Encoding {
+	var encodings []Encoding
+
+	for part := range strings.SplitSeq(value, ",") {
+		encoding := Encoding(strings.ToLower(strings.TrimSpace(part)))
+		if encoding == "" || encoding == "identity" {
+			continue
+		}
+
+		encodings = append(encodings, encoding)
+	}
+
+	return encodings
+}
+
+func responseHasBody(resp *http.Response) bool {
+	if resp.Body == nil || resp.Body == http.NoBody {
+		return false
+	}
+
+	if resp.Request != nil && resp.Request.Method == http.MethodHead {
+		return false
+	}
+
+	return resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusNotModified
+}
+
+// decodingReader decodes body on the first Read, so empty bodies sent with
+// a Content-Encoding read as empty instead of failing.
+type decodingReader struct {
+	body      io.ReadCloser
+	encodings []Encoding
+	// original is the Content-Encoding header the body was sent with.
+	original string
+
+	reader   io.Reader
+	decoders []io.Closer
+	err      error
+}
+
+func (d *decodingReader) Read(p []byte) (int, error) {
+	if d.reader == nil && d.err == nil {
+		d.err = d.init()
+	}
+
+	if d.err != nil {
+		return 0, d.err
+	}
+
+	return d.reader.Read(p)
+}
+
+// init layers the decoders over the body, undoing the last encoding first.
+func (d *decodingReader) init() error {
+	var reader io.Reader = d.body
+
+	for _, encoding := range slices.Backward(d.encodings) {
+		decoder, err := NewReader(reader, encoding)
+		if err != nil {
+			return err
+		}
+
+		d.decoders = append(d.decoders, decoder)
+		reader = decoder
+	}
+
+	d.reader = reader
+
+	return nil
+}
+
+func (d *decodingReader) Close() error {
+	var errs []error
+
+	for _, decoder := range slices.Backward(d.decoders) {
+		errs = append(errs, decoder.Close())
+	}
+
+	return errors.Join(append(errs, d.body.Close())...)
+}
@@ -0,0 +1,164 @@
+package compression
+
+import (
+	"bytes"
+	"compress/flate"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/jeanmolossi/maigo/pkg/httpx"
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/require"
+)
+
+func encodeBody(t *testing.T, data string, encodings ...Encoding) []byte {
+	t.Helper()
+
+	out := []byte(data)
+
+	for _, encoding := range encodings {
+		var err error
+
+		out, err = Compress(out, encoding)
+		require.NoError(t, err)
+	}
+
+	return out
+}
+
+func rawDeflate(t *testing.T, data string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	writer, err := flate.NewWriter(&buf, flate.DefaultCompression)
+	require.NoError(t, err)
+
+	_, err = writer.Write([]byte(data))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	return buf.Bytes()
+}
+
+func encodedResp(encoding string, body []byte) *http.Response {
+	resp := httpx.NewResp(http.StatusOK, "")
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	resp.ContentLength = int64(len(body))
+	resp.Header.Set("Content-Encoding", encoding)
+	resp.Header.Set("Content-Length", "42")
+
+	return resp
+}
+
+func TestWithDecompression_Encodings(t *testing.T) {
+	t.Parallel()
+
+	const payload = `{"name":"mai"}`
+
+	tests := []struct {
+		name     string
+		encoding string
+		body     []byte
+	}{
+		{name: "gzip", encoding: "gzip", body: encodeBody(t, payload, Gzip)},
+		{name: "deflate", encoding: "deflate", body: encodeBody(t, payload, Deflate)},
+		{name: "raw deflate", encoding: "deflate", body: rawDeflate(t, payload)},
+		{name: "brotli", encoding: "br", body: encodeBody(t, payload, Brotli)},
+		{name: "zstd", encoding: "zstd", body: encodeBody(t, payload, Zstd)},
+		{name: "stacked", encoding: "gzip, br", body: encodeBody(t, payload, Gzip, Brotli)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			base, _ := httpx.NewRoundTripMockBuilder().
+				AddOutcome(encodedResp(tt.encoding, tt.body), nil).
+				Build(t)
+
+			req, err := http.NewRequest(http.MethodGet, "http://x", nil)
+			require.NoError(t, err)
+
+			// set by hand, which disables the transport decompression
+			req.Header.Set("Accept-Encoding", "gzip")
+
+			resp, err := WithDecompression(DecompressionConfig{})(base).RoundTrip(req)
+			require.NoError(t, err)
+
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			require.NoError(t, resp.Body.Close())
+
+			require.Equal(t, payload, string(body))
+			require.Empty(t, resp.Header.Get("Content-Encoding"))
+			require.Empty(t, resp.Header.Get("Content-Length"))
+			require.Equal(t, int64(-1), resp.ContentLength)
+			require.True(t, resp.Uncompressed)
+			require.Equal(t, tt.encoding, OriginalEncoding(resp))
+		})
+	}
+}
+
+func TestWithDecompression_AdvertisesEncodings(t *testing.T) {
+	t.Parallel()
+
+	base, assert := httpx.NewRoundTripMockBuilder().Build(t)
+
+	req, err := http.NewRequest(http.MethodGet, "http://x", nil)
+	require.NoError(t, err)
+
+	_, err = WithDecompression(DecompressionConfig{Encodings: []Encoding{Zstd, Gzip}})(base).RoundTrip(req)
+	require.NoError(t, err)
+
+	assert.SeenHeaders(0, "Accept-Encoding", "zstd, gzip")
+	require.Empty(t, req.Header.Get("Accept-Encoding"), "caller request modified")
+}
+
+func TestWithDecompression_LeavesBodies(t *testing.T) {
+	t.Parallel()
+
+	unsupported := encodedResp("compress", []byte("lzw"))
+
+	empty := encodedResp("gzip", nil)
+	empty.StatusCode = http.StatusNoContent
+
+	for _, resp := range []*http.Response{unsupported, empty} {
+		base, _ := httpx.NewRoundTripMockBuilder().AddOutcome(resp, nil).Build(t)
+
+		req, err := http.NewRequest(http.MethodGet, "http://x", nil)
+		require.NoError(t, err)
+
+		got, err := WithDecompression(DecompressionConfig{})(base).RoundTrip(req)
+		require.NoError(t, err)
+		require.NotEmpty(t, got.Header.Get("Content-Encoding"))
+		require.Empty(t, OriginalEncoding(got))
+	}
+
+	// an empty body sent with an encoding reads as empty
+	body := &decodingReader{body: io.NopCloser(strings.NewReader("")), encodings: []Encoding{Gzip}}
+
+	data, err := io.ReadAll(body)
+	require.NoError(t, err)
+	require.Empty(t, data)
+}
+
+func TestNewReader_ZstdWindowLimit(t *testing.T) {
+	t.Parallel()
+
+	encoder, err := zstd.NewWriter(nil, zstd.WithWindowSize(32<<20))
+	require.NoError(t, err)
+
+	// the frame announces a window above the limit
+	data := encoder.EncodeAll(make([]byte, 16<<20), nil)
+	require.NoError(t, encoder.Close())
+
+	reader, err := NewReader(bytes.NewReader(data), Zstd)
+	require.NoError(t, err)
+
+	_, err = io.Copy(io.Discard, reader)
+	require.Error(t, err)
+	require.NoError(t, reader.Close())
+}
@@ -0,0 +1,4 @@
+// Package compression provides the HTTP content codings gzip, deflate, zstd
+// and brotli, with middlewares compressing the bodies of outgoing requests
+// and decompressing the bodies of incoming responses.
+package compression
@@ -0,0 +1,76 @@
+package compression
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+
+	"github.com/jeanmolossi/maigo/pkg/httpx"
+)
+
+// CompressionConfig contains settings for the compression round tripper.
+type CompressionConfig struct {
+	// Encoding is the content coding applied to request bodies. Defaults to
+	// gzip.
+	Encoding Encoding
+	// MinSize is the size in bytes below which bodies are sent as is.
+	MinSize int64
+}
+
+// WithCompression wraps the next RoundTripper compressing request bodies of
+// at least cfg.MinSize bytes and setting their Content-Encoding. Requests
+// already carrying a Content-Encoding are sent untouched. Bodies are
+// buffered to be compressed, so the request stays replayable through
+// GetBody.
+func WithCompression(cfg CompressionConfig) httpx.ChainedRoundTripper {
+	if cfg.Encoding == "" {
+		cfg.Encoding = Gzip
+	}
+
+	return func(next http.RoundTripper) http.RoundTripper {
+		return httpx.RoundTripperFn(func(r *http.Request) (*http.Response, error) {
+			if !cfg.Encoding.Supported() || !hasBody(r) || r.Header.Get("Content-Encoding") != "" {
+				return next.RoundTrip(r)
+			}
+
+			if r.ContentLength > 0 && r.ContentLength < cfg.MinSize {
+				return next.RoundTrip(r)
+			}
+
+			data, err := io.ReadAll(r.Body)
+			_ = r.Body.Close()
+
+			if err != nil {
+				return nil, err
+			}
+
+			encoding := ""
+
+			if int64(len(data)) >= cfg.MinSize {
+				if data, err = Compress(data, cfg.Encoding); err != nil {
+					return nil, err
+				}
+
+				encoding = cfg.Encoding.String()
+			}
+
+			// the caller request must not be modified
+			compressed := r.Clone(r.Context())
+			compressed.Body = io.NopCloser(bytes.NewReader(data))
+			compressed.ContentLength = int64(len(data))
+			compressed.GetBody = func() (io.ReadCloser, error) {
+				return io.NopCloser(bytes.NewReader(data)), nil
+			}
+
+			if encoding != "" {
+				compressed.Header.Set("Content-Encoding", encoding)
+			}
+
+			return next.RoundTrip(compressed)
+		})
+	}
+}
+
+func hasBody(r *http.Request) bool {
+	return r.Body != nil && r.Body != http.NoBody
+}
@@ -0,0 +1,144 @@
+package compression
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/jeanmolossi/maigo/pkg/httpx"
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/require"
+)
+
+func decodeBody(t *testing.T, encoding string, data []byte) string {
+	t.Helper()
+
+	var (
+		reader io.Reader
+		err    error
+	)
+
+	switch encoding {
+	case "gzip":
+		reader, err = gzip.NewReader(bytes.NewReader(data))
+	case "deflate":
+		reader, err = zlib.NewReader(bytes.NewReader(data))
+	case "zstd":
+		reader, err = zstd.NewReader(bytes.NewReader(data))
+	default:
+		return string(data)
+	}
+
+	require.NoError(t, err)
+
+	out, err := io.ReadAll(reader)
+	require.NoError(t, err)
+
+	return string(out)
+}
+
+// captureBodies records the decompressed body of every request, reading it
+// again through GetBody to check the request can be replayed.
+func captureBodies(t *testing.T, bodies *[]string, encodings *[]string) http.RoundTripper {
+	t.Helper()
+
+	return httpx.RoundTripperFn(func(r *http.Request) (*http.Response, error) {
+		data, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		replay, err := r.GetBody()
+		require.NoError(t, err)
+
+		replayed, err := io.ReadAll(replay)
+		require.NoError(t, err)
+		require.Equal(t, data, replayed, "replayed body mismatch")
+		require.Equal(t, int64(len(data)), r.ContentLength)
+
+		encoding := r.Header.Get("Content-Encoding")
+		*bodies = append(*bodies, decodeBody(t, encoding, data))
+		*encodings = append(*encodings, encoding)
+
+		return httpx.NewResp(http.StatusOK, ""), nil
+	})
+}
+
+func TestWithCompression_Encodings(t *testing.T) {
+	t.Parallel()
+
+	payload := strings.Repeat(`{"id":1,"name":"mai"}`, 100)
+
+	for _, encoding := range []Encoding{Gzip, Deflate, Zstd} {
+		t.Run(encoding.String(), func(t *testing.T) {
+			t.Parallel()
+
+			var bodies, encodings []string
+
+			rt := WithCompression(CompressionConfig{Encoding: encoding, MinSize: 1024})(captureBodies(t, &bodies, &encodings))
+
+			req, err := http.NewRequest(http.MethodPost, "http://x", strings.NewReader(payload))
+			require.NoError(t, err)
+
+			_, err = rt.RoundTrip(req)
+			require.NoError(t, err)
+
+			require.Equal(t, []string{payload}, bodies)
+			require.Equal(t, []string{encoding.String()}, encodings)
+			require.Empty(t, req.Header.Get("Content-Encoding"), "caller request modified")
+		})
+	}
+}
+
+func TestWithCompression_Skips(t *testing.T) {
+	t.Parallel()
+
+	var bodies, encodings []string
+
+	rt := WithCompression(CompressionConfig{MinSize: 1024})(captureBodies(t, &bodies, &encodings))
+
+	small, err := http.NewRequest(http.MethodPost, "http://x", strings.NewReader("small"))
+	require.NoError(t, err)
+
+	// unknown length, read before deciding
+	unknown, err := http.NewRequest(http.MethodPost, "http://x", io.NopCloser(strings.NewReader("tiny")))
+	require.NoError(t, err)
+
+	_, err = rt.RoundTrip(small)
+	require.NoError(t, err)
+
+	_, err = rt.RoundTrip(unknown)
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"small", "tiny"}, bodies)
+	require.Equal(t, []string{"", ""}, encodings)
+
+	base, assert := httpx.NewRoundTripMockBuilder().Build(t)
+
+	encoded, err := http.NewRequest(http.MethodPost, "http://x", strings.NewReader(strings.Repeat("a", 2048)))
+	require.NoError(t, err)
+	encoded.Header.Set("Content-Encoding", "br")
+
+	_, err = WithCompression(CompressionConfig{})(base).RoundTrip(encoded)
+	require.NoError(t, err)
+
+	assert.SeenBodies(0, strings.Repeat("a", 2048), "pre-encoded body changed")
+	assert.SeenHeaders(0, "Content-Encoding", "br")
+}
+
+func TestCompressReader(t *testing.T) {
+	t.Parallel()
+
+	reader, err := CompressReader(strings.NewReader("streamed"), Zstd)
+	require.NoError(t, err)
+
+	data, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	require.NoError(t, reader.Close())
+	require.Equal(t, "streamed", decodeBody(t, "zstd", data))
+
+	_, err = CompressReader(strings.NewReader(""), "compress")
+	require.ErrorIs(t, err, ErrUnsupportedEncoding)
+}
@@ -31,6 +31,11 @@ type RoundTripperOptions struct {
 	DurationCollector *prometheus.HistogramVec
 	// CountCollector allows providing a pre-constructed counter vector.
 	CountCollector *prometheus.CounterVec
+
+	// RouteLabel adds a "route" label holding the path template of the
+	// request, see httpx.WithRoute, or an empty value when there is none.
+	// Pre-constructed collectors must declare the label as well.
+	RouteLabel bool
 }
 
 const (
@@ -39,7 +44,8 @@ const (
 )
 
 // MetricsRoundTripper instruments an HTTP client transport recording request
-// durations and counts labelled by method and status code.
+// durations and counts labelled by method and status code, and optionally by
+// route.
 func MetricsRoundTripper(opts RoundTripperOptions) httpx.ChainedRoundTripper {
 	duration := opts.DurationCollector
 	count := opts.CountCollector
@@ -49,6 +55,11 @@ func MetricsRoundTripper(opts RoundTripperOptions) httpx.ChainedRoundTripper {
 		registerer = prometheus.DefaultRegisterer
 	}
 
+	labelNames := []string{"method", "status"}
+	if opts.RouteLabel {
+		labelNames = append(labelNames, "route")
+	}
+
 	if duration == nil {
 		duration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
 			Namespace: opts.Namespace,
@@ -56,7 +67,7 @@ func MetricsRoundTripper(opts RoundTripperOptions) httpx.ChainedRoundTripper {
 			Name:      metricName(opts.DurationName, defaultDurationName),
 			Help:      "Duration of outbound HTTP requests",
 			Buckets:   bucketsOrDefault(opts.DurationBuckets),
-		}, []string{"method", "status"})
+		}, labelNames)
 	}
 
 	duration = registerHistogram(registerer, duration)
@@ -67,7 +78,7 @@ func MetricsRoundTripper(opts RoundTripperOptions) httpx.ChainedRoundTripper {
 			Subsystem: opts.Subsystem,
 			Name:      metricName(opts.CountName, defaultCountName),
 			Help:      "Total number of outbound HTTP requests",
-		}, []string{"method", "status"})
+		}, labelNames)
 	}
 
 	count = registerCounter(registerer, count)
@@ -88,6 +99,10 @@ func MetricsRoundTripper(opts RoundTripperOptions) httpx.ChainedRoundTripper {
 				"status": status,
 			}
 
+			if opts.RouteLabel {
+				labels["route"], _ = httpx.RouteFromContext(r.Context())
+			}
+
 			duration.With(labels).Observe(elapsed)
 			count.With(labels).Inc()
 
@@ -66,6 +66,35 @@ func TestMetricsRoundTripper_IncrementsErrorMetrics(t *testing.T) {
 	requireHistogramSampleCount(t, registry, "maigo_test_request_duration_seconds", http.MethodGet, "error", 1)
 }
 
+func TestMetricsRoundTripper_RouteLabel(t *testing.T) {
+	t.Parallel()
+
+	registry := prometheus.NewRegistry()
+	counter := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "maigo_test_requests_total"}, []string{"method", "status", "route"})
+
+	rt := MetricsRoundTripper(RoundTripperOptions{
+		Registerer:     registry,
+		CountCollector: counter,
+		RouteLabel:     true,
+	})
+
+	next := httpx.RoundTripperFn(func(r *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+	})
+
+	for _, id := range []string{"1", "2"} {
+		req, err := http.NewRequest(http.MethodGet, "http://example.com/users/"+id, http.NoBody)
+		require.NoError(t, err)
+
+		req = req.WithContext(httpx.WithRoute(req.Context(), "/users/{id}"))
+
+		_, err = rt(next).RoundTrip(req)
+		require.NoError(t, err)
+	}
+
+	require.InEpsilon(t, 2, testutil.ToFloat64(counter.WithLabelValues(http.MethodGet, "200", "/users/{id}")), 0.0001)
+}
+
 func requireHistogramSampleCount(t *testing.T, registry *prometheus.Registry, metricName, method, status string, expected uint64) {
 	t.Helper()
 
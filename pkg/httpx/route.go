@@ -0,0 +1,18 @@
+package httpx
+
+import "context"
+
+type routeKey struct{}
+
+// WithRoute returns a copy of ctx carrying route, the path template a request
+// was built from, such as "/users/{id}". Middlewares use it to label requests
+// without the cardinality of the actual path.
+func WithRoute(ctx context.Context, route string) context.Context {
+	return context.WithValue(ctx, routeKey{}, route)
+}
+
+// RouteFromContext returns the route stored in ctx by [WithRoute].
+func RouteFromContext(ctx context.Context) (string, bool) {
+	route, ok := ctx.Value(routeKey{}).(string)
+	return route, ok && route != ""
+}
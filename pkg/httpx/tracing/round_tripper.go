@@ -16,7 +16,8 @@ import (
 const tracerName = "github.com/jeanmolossi/maigo/pkg/httpx/tracing"
 
 // WithTracing starts a span for each outbound HTTP request and propagates the
-// span context via request headers.
+// span context via request headers. Requests carrying a route, see
+// [httpx.WithRoute], are named after it and record it as http.route.
 func WithTracing() httpx.ChainedRoundTripper {
 	tracer := otel.Tracer(tracerName)
 	propagator := otel.GetTextMapPropagator()
@@ -25,9 +26,16 @@ func WithTracing() httpx.ChainedRoundTripper {
 		return httpx.RoundTripperFn(func(req *http.Request) (*http.Response, error) {
 			ctx := req.Context()
 
+			name := fmt.Sprintf("%s %s", req.Method, req.URL.RequestURI())
+
+			route, hasRoute := httpx.RouteFromContext(ctx)
+			if hasRoute {
+				name = fmt.Sprintf("%s %s", req.Method, route)
+			}
+
 			ctx, span := tracer.Start(
 				ctx,
-				fmt.Sprintf("%s %s", req.Method, req.URL.RequestURI()),
+				name,
 				trace.WithSpanKind(trace.SpanKindClient),
 			)
 			defer span.End()
@@ -50,6 +58,10 @@ func WithTracing() httpx.ChainedRoundTripper {
 				attribute.String("http.target", req.URL.Path),
 			)
 
+			if hasRoute {
+				span.SetAttributes(semconv.HTTPRouteKey.String(route))
+			}
+
 			return resp, nil
 		})
 	}
@@ -13,6 +13,7 @@ import (
 	"go.opentelemetry.io/otel/propagation"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
 	"go.opentelemetry.io/otel/trace"
 )
 
@@ -71,6 +72,24 @@ func TestWithTracingPropagatesContextAndRecordsErrors(t *testing.T) {
 	require.True(t, span.SpanContext().TraceID().IsValid())
 }
 
+func TestWithTracingNamesSpanAfterRoute(t *testing.T) {
+	spanRecorder, restore := installTracer(t)
+	t.Cleanup(restore)
+
+	req := mustRequest(http.MethodGet, "https://example.com/users/42")
+	req = req.WithContext(httpx.WithRoute(req.Context(), "/users/{id}"))
+
+	mock, _ := httpx.NewRoundTripMockBuilder().Build(t)
+
+	_, err := httpx.Compose(mock, WithTracing()).RoundTrip(req)
+	require.NoError(t, err)
+
+	ended := spanRecorder.Ended()
+	require.Len(t, ended, 1)
+	require.Equal(t, "GET /users/{id}", ended[0].Name())
+	require.Contains(t, ended[0].Attributes(), semconv.HTTPRouteKey.String("/users/{id}"))
+}
+
 func installTracer(t *testing.T) (*tracetest.SpanRecorder, func()) {
 	t.Helper()
 
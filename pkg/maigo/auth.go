@@ -0,0 +1,92 @@
+package maigo
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+
+	"github.com/jeanmolossi/maigo/pkg/maigo/contracts"
+	"github.com/jeanmolossi/maigo/pkg/maigo/header"
+	"golang.org/x/net/http/httpguts"
+)
+
+var (
+	_ contracts.Authenticator = (*HeaderAuth)(nil)
+	_ contracts.Authenticator = (*QueryAuth)(nil)
+)
+
+type (
+	// HeaderAuth sends credentials in a request header, such as Authorization
+	// or an API key header like X-API-Key.
+	HeaderAuth struct {
+		key   header.Type
+		value string
+	}
+
+	// QueryAuth sends credentials in a query parameter.
+	QueryAuth struct {
+		param string
+		value string
+	}
+)
+
+// Authenticate implements contracts.Authenticator.
+func (h *HeaderAuth) Authenticate(r *http.Request) error {
+	r.Header.Set(h.key.String(), h.value)
+	return nil
+}
+
+// Authenticate implements contracts.Authenticator.
+func (q *QueryAuth) Authenticate(r *http.Request) error {
+	query := r.URL.Query()
+	query.Set(q.param, q.value)
+	r.URL.RawQuery = query.Encode()
+
+	return nil
+}
+
+// NewHeaderAuth creates an authenticator that sends value in the key header.
+// It fails when key or value are not valid according to RFC 9110.
+func NewHeaderAuth(key header.Type, value string) (*HeaderAuth, error) {
+	if !httpguts.ValidHeaderFieldName(key.String()) {
+		return nil, fmt.Errorf("%w: invalid header name %q", ErrInvalidAuth, key)
+	}
+
+	if value == "" || !httpguts.ValidHeaderFieldValue(value) {
+		return nil, fmt.Errorf("%w: invalid %s value", ErrInvalidAuth, key)
+	}
+
+	return &HeaderAuth{key: key, value: value}, nil
+}
+
+// NewBearerAuth creates an authenticator that sends token as a Bearer
+// Authorization header.
+func NewBearerAuth(token string) (*HeaderAuth, error) {
+	if token == "" {
+		return nil, fmt.Errorf("%w: empty bearer token", ErrInvalidAuth)
+	}
+
+	return NewHeaderAuth(header.Authorization, "Bearer "+token)
+}
+
+// NewBasicAuth creates an authenticator that sends user and pass as a Basic
+// Authorization header as defined by RFC 7617.
+func NewBasicAuth(user, pass string) (*HeaderAuth, error) {
+	credentials := base64.StdEncoding.EncodeToString([]byte(user + ":" + pass))
+
+	return NewHeaderAuth(header.Authorization, "Basic "+credentials)
+}
+
+// NewQueryAuth creates an authenticator that sends value in the param query
+// parameter.
+func NewQueryAuth(param, value string) (*QueryAuth, error) {
+	if param == "" {
+		return nil, fmt.Errorf("%w: empty query parameter name", ErrInvalidAuth)
+	}
+
+	if value == "" {
+		return nil, fmt.Errorf("%w: empty %s value", ErrInvalidAuth, param)
+	}
+
+	return &QueryAuth{param: param, value: value}, nil
+}
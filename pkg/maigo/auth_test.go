@@ -0,0 +1,140 @@
+package maigo
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/jeanmolossi/maigo/pkg/maigo/header"
+)
+
+func TestClientAuth_BearerToken(t *testing.T) {
+	t.Parallel()
+
+	client := NewClient("https://example.com").
+		Auth().BearerToken("client-token").
+		Build()
+
+	req, err := client.GET("/users").Unwrap()
+	if err != nil {
+		t.Fatalf("Unwrap() error = %v", err)
+	}
+
+	if got := req.Header.Get(header.Authorization.String()); got != "Bearer client-token" {
+		t.Fatalf("Authorization = %q, want %q", got, "Bearer client-token")
+	}
+}
+
+func TestRequestAuth_TakesPrecedenceOverClient(t *testing.T) {
+	t.Parallel()
+
+	client := NewClient("https://example.com").
+		Auth().APIKeyQuery("api_key", "client-key").
+		Build()
+
+	req, err := client.GET("/users").
+		Auth().BasicAuth("mai", "sakurajima").
+		Unwrap()
+	if err != nil {
+		t.Fatalf("Unwrap() error = %v", err)
+	}
+
+	user, pass, ok := req.BasicAuth()
+	if !ok || user != "mai" || pass != "sakurajima" {
+		t.Fatalf("BasicAuth() = %q, %q, %v, want mai, sakurajima, true", user, pass, ok)
+	}
+
+	if got := req.URL.Query().Get("api_key"); got != "" {
+		t.Fatalf("client api key leaked into request query: %q", got)
+	}
+}
+
+func TestAuth_APIKeyModes(t *testing.T) {
+	t.Parallel()
+
+	client := NewClient("https://example.com").Build()
+
+	t.Run("header", func(t *testing.T) {
+		t.Parallel()
+
+		req, err := client.GET("/").Auth().APIKeyHeader("X-API-Key", "secret").Unwrap()
+		if err != nil {
+			t.Fatalf("Unwrap() error = %v", err)
+		}
+
+		if got := req.Header.Get("X-API-Key"); got != "secret" {
+			t.Fatalf("X-API-Key = %q, want %q", got, "secret")
+		}
+	})
+
+	t.Run("query keeps other params", func(t *testing.T) {
+		t.Parallel()
+
+		req, err := client.GET("/").
+			Query().AddParam("page", "2").
+			Auth().APIKeyQuery("api_key", "secret").
+			Unwrap()
+		if err != nil {
+			t.Fatalf("Unwrap() error = %v", err)
+		}
+
+		query := req.URL.Query()
+		if query.Get("api_key") != "secret" || query.Get("page") != "2" {
+			t.Fatalf("query = %q, want api_key=secret&page=2", req.URL.RawQuery)
+		}
+	})
+}
+
+func TestAuth_InvalidCredentials(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		build func() (*http.Request, error)
+	}{
+		{
+			name: "empty bearer on client",
+			build: func() (*http.Request, error) {
+				return NewClient("https://example.com").Auth().BearerToken("").Build().GET("/").Unwrap()
+			},
+		},
+		{
+			name: "header injection on request",
+			build: func() (*http.Request, error) {
+				return DefaultClient("https://example.com").GET("/").Auth().Set("Bearer a\r\nX-Evil: 1").Unwrap()
+			},
+		},
+		{
+			name: "nil custom authenticator",
+			build: func() (*http.Request, error) {
+				return DefaultClient("https://example.com").GET("/").Auth().Custom(nil).Unwrap()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := tt.build()
+			if !errors.Is(err, ErrInvalidAuth) {
+				t.Fatalf("Unwrap() error = %v, want %v", err, ErrInvalidAuth)
+			}
+		})
+	}
+}
+
+type failingAuth struct{}
+
+func (failingAuth) Authenticate(*http.Request) error {
+	return errors.New("no credentials available")
+}
+
+func TestAuth_CustomAuthenticatorError(t *testing.T) {
+	t.Parallel()
+
+	_, err := DefaultClient("https://example.com").GET("/").Auth().Custom(failingAuth{}).Unwrap()
+	if !errors.Is(err, ErrAuthenticate) {
+		t.Fatalf("Unwrap() error = %v, want %v", err, ErrAuthenticate)
+	}
+}
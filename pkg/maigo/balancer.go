@@ -0,0 +1,188 @@
+package maigo
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/jeanmolossi/maigo/pkg/maigo/contracts"
+)
+
+// BalancerOption configures the load balancing of NewClientLoadBalancer.
+type BalancerOption func(*balancerConfig)
+
+type balancerConfig struct {
+	strategy contracts.BalanceStrategy
+	weights  []int
+}
+
+// WithStrategy sets how the backend of each request is chosen. Strategies
+// keep state, so each client needs its own.
+func WithStrategy(strategy contracts.BalanceStrategy) BalancerOption {
+	return func(c *balancerConfig) {
+		c.strategy = strategy
+	}
+}
+
+// WithWeights sets the weight of each base URL, in the same order. Weights
+// are used by WeightedRoundRobin, Random, LeastInFlight and
+// PowerOfTwoChoices; every base URL weighs 1 by default.
+func WithWeights(weights ...int) BalancerOption {
+	return func(c *balancerConfig) {
+		c.weights = weights
+	}
+}
+
+// validate reports the options that cannot balance size base URLs.
+func (c *balancerConfig) validate(size int) []error {
+	var errs []error
+
+	if len(c.weights) > 0 && len(c.weights) != size {
+		errs = append(errs, fmt.Errorf("%w: %d weights for %d base URLs", ErrInvalidBalancer, len(c.weights), size))
+	}
+
+	for index, weight := range c.weights {
+		if weight < 1 {
+			errs = append(errs, fmt.Errorf("%w: weight %d of base URL %d must be positive", ErrInvalidBalancer, weight, index))
+		}
+	}
+
+	return errs
+}
+
+// RoundRobin takes the backends in turn, ignoring their weights. It is the
+// default strategy.
+func RoundRobin() contracts.BalanceStrategy {
+	return &roundRobin{}
+}
+
+type roundRobin struct {
+	next atomic.Uint32
+}
+
+// Pick implements contracts.BalanceStrategy.
+func (r *roundRobin) Pick(backends []contracts.Backend) int {
+	idx := r.next.Add(1) - 1
+
+	return int(idx % uint32(len(backends)))
+}
+
+// WeightedRoundRobin takes the backends in turn in proportion to their
+// weights, interleaving them: weights 2 and 1 give a, b, a rather than
+// a, a, b.
+func WeightedRoundRobin() contracts.BalanceStrategy {
+	return &weightedRoundRobin{}
+}
+
+// weightedRoundRobin is the smooth weighted round-robin of nginx.
+type weightedRoundRobin struct {
+	mu      sync.Mutex
+	current []int
+}
+
+// Pick implements contracts.BalanceStrategy.
+func (w *weightedRoundRobin) Pick(backends []contracts.Backend) int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if len(w.current) != len(backends) {
+		w.current = make([]int, len(backends))
+	}
+
+	best, total := 0, 0
+
+	for i, backend := range backends {
+		weight := backend.Weight()
+		total += weight
+		w.current[i] += weight
+
+		if w.current[i] > w.current[best] {
+			best = i
+		}
+	}
+
+	w.current[best] -= total
+
+	return best
+}
+
+// Random picks a backend at random, with a chance proportional to its
+// weight.
+func Random() contracts.BalanceStrategy {
+	return random{}
+}
+
+type random struct{}
+
+// Pick implements contracts.BalanceStrategy.
+func (random) Pick(backends []contracts.Backend) int {
+	total := 0
+	for _, backend := range backends {
+		total += backend.Weight()
+	}
+
+	n := int(secureFloat64() * float64(total))
+
+	for i, backend := range backends {
+		if n -= backend.Weight(); n < 0 {
+			return i
+		}
+	}
+
+	return len(backends) - 1
+}
+
+// LeastInFlight picks the backend with the fewest requests in flight for its
+// weight. Ties are broken in turn, so idle backends share the load.
+func LeastInFlight() contracts.BalanceStrategy {
+	return &leastInFlight{}
+}
+
+type leastInFlight struct {
+	next atomic.Uint32
+}
+
+// Pick implements contracts.BalanceStrategy.
+func (l *leastInFlight) Pick(backends []contracts.Backend) int {
+	start := int((l.next.Add(1) - 1) % uint32(len(backends)))
+	best := start
+
+	for offset := 1; offset < len(backends); offset++ {
+		i := (start + offset) % len(backends)
+		if lessLoaded(backends[i], backends[best]) {
+			best = i
+		}
+	}
+
+	return best
+}
+
+// PowerOfTwoChoices picks two backends at random and takes the one with the
+// fewest requests in flight for its weight. It spreads the load nearly as
+// well as LeastInFlight without comparing every backend.
+func PowerOfTwoChoices() contracts.BalanceStrategy {
+	return powerOfTwoChoices{}
+}
+
+type powerOfTwoChoices struct{}
+
+// Pick implements contracts.BalanceStrategy.
+func (powerOfTwoChoices) Pick(backends []contracts.Backend) int {
+	size := len(backends)
+
+	first := int(secureFloat64() * float64(size))
+	// a second, distinct backend
+	second := (first + 1 + int(secureFloat64()*float64(size-1))) % size
+
+	if lessLoaded(backends[second], backends[first]) {
+		return second
+	}
+
+	return first
+}
+
+// lessLoaded reports whether a has fewer requests in flight than b relative
+// to their weights.
+func lessLoaded(a, b contracts.Backend) bool {
+	return a.InFlight()*int64(b.Weight()) < b.InFlight()*int64(a.Weight())
+}
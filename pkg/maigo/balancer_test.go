@@ -0,0 +1,262 @@
+package maigo
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"testing"
+
+	"github.com/jeanmolossi/maigo/pkg/maigo/contracts"
+)
+
+func newTestBalancer(t *testing.T, weights []int, strategy BalancerOption) *BalancedBaseURL {
+	t.Helper()
+
+	raw := []string{"https://a.example.com", "https://b.example.com", "https://c.example.com"}
+
+	urls := make([]*url.URL, len(raw))
+	for i, r := range raw {
+		urls[i] = mustParse(t, r)
+	}
+
+	var config balancerConfig
+	strategy(&config)
+
+	return newBalancedBaseURL(urls, weights, config.strategy)
+}
+
+func hosts(t *testing.T, b *BalancedBaseURL, n int) []string {
+	t.Helper()
+
+	picked := make([]string, n)
+	for i := range picked {
+		u, release := b.AcquireBaseURL()
+		release()
+
+		picked[i] = u.Hostname()[:1]
+	}
+
+	return picked
+}
+
+func TestBalancer_WeightedRoundRobin(t *testing.T) {
+	t.Parallel()
+
+	b := newTestBalancer(t, []int{4, 2, 1}, WithStrategy(WeightedRoundRobin()))
+
+	// smooth: the heaviest backend is not picked in a burst
+	want := []string{"a", "b", "a", "c", "a", "b", "a", "a", "b", "a", "c", "a", "b", "a"}
+	if got := hosts(t, b, len(want)); !slices.Equal(got, want) {
+		t.Fatalf("picks = %v, want %v", got, want)
+	}
+}
+
+func TestBalancer_Random(t *testing.T) {
+	t.Parallel()
+
+	b := newTestBalancer(t, []int{1, 1, 4}, WithStrategy(Random()))
+
+	counts := map[string]int{}
+	for _, host := range hosts(t, b, 6000) {
+		counts[host]++
+	}
+
+	if counts["a"] == 0 || counts["b"] == 0 || counts["c"] < 3*counts["a"] {
+		t.Fatalf("counts = %v, want c picked about four times as often as a", counts)
+	}
+}
+
+func TestBalancer_LeastInFlight(t *testing.T) {
+	t.Parallel()
+
+	b := newTestBalancer(t, []int{2, 1, 1}, WithStrategy(LeastInFlight()))
+
+	// a takes two requests for its weight before the others take one
+	var releases []func()
+
+	for range 4 {
+		_, release := b.AcquireBaseURL()
+		releases = append(releases, release)
+	}
+
+	inFlight := func() []int64 {
+		var counts []int64
+		for _, backend := range b.Backends() {
+			counts = append(counts, backend.InFlight())
+		}
+
+		return counts
+	}
+
+	if got := inFlight(); !slices.Equal(got, []int64{2, 1, 1}) {
+		t.Fatalf("in flight = %v, want [2 1 1]", got)
+	}
+
+	releases[1]()
+	releases[1]()
+
+	if got := hosts(t, b, 1); got[0] != "b" {
+		t.Fatalf("AcquireBaseURL() = %q, want the backend released", got)
+	}
+
+	for _, release := range releases {
+		release()
+	}
+
+	if got := inFlight(); !slices.Equal(got, []int64{0, 0, 0}) {
+		t.Fatalf("in flight after release = %v, want none", got)
+	}
+}
+
+func TestBalancer_PowerOfTwoChoices(t *testing.T) {
+	t.Parallel()
+
+	b := newTestBalancer(t, nil, WithStrategy(PowerOfTwoChoices()))
+	b.backends[0].(*backend).inFlight.Store(100)
+
+	// the busy backend loses every comparison
+	counts := map[string]int{}
+	for _, host := range hosts(t, b, 200) {
+		counts[host]++
+	}
+
+	if counts["a"] != 0 || counts["b"] == 0 || counts["c"] == 0 {
+		t.Fatalf("counts = %v, want only the idle backends", counts)
+	}
+}
+
+func TestNewClientLoadBalancer_InvalidOptions(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		opts []BalancerOption
+	}{
+		{name: "weights count", opts: []BalancerOption{WithWeights(1)}},
+		{name: "non positive weight", opts: []BalancerOption{WithWeights(1, 0)}},
+	}
+
+	for _, tt := range tests {
+		client := NewClientLoadBalancer([]string{"https://a.example.com", "https://b.example.com"}, tt.opts...).Build()
+
+		if _, err := client.GET("/").Send(); !errors.Is(err, ErrInvalidBalancer) {
+			t.Errorf("%s: Send() error = %v, want ErrInvalidBalancer", tt.name, err)
+		}
+	}
+}
+
+func TestNewClientLoadBalancer_ReleasesOnCompletion(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("mai"))
+	}))
+	t.Cleanup(server.Close)
+
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+
+	client := NewClientLoadBalancer(
+		[]string{server.URL, closed.URL},
+		WithStrategy(LeastInFlight()),
+	).Build()
+
+	balancer := client.(*ClientConfigBase).ConfigBaseURL.(*BalancedBaseURL)
+
+	inFlight := func() int64 {
+		var total int64
+		for _, backend := range balancer.Backends() {
+			total += backend.InFlight()
+		}
+
+		return total
+	}
+
+	resp, err := client.GET("/").Send()
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	// in flight until the body is read
+	if got := inFlight(); got != 1 {
+		t.Fatalf("in flight before reading = %d, want 1", got)
+	}
+
+	if _, err := resp.Body().AsString(); err != nil {
+		t.Fatalf("AsString() error = %v", err)
+	}
+
+	if got := inFlight(); got != 0 {
+		t.Fatalf("in flight after reading = %d, want 0", got)
+	}
+
+	// the idle backend refuses the connection
+	if _, err := client.GET("/").Send(); err == nil {
+		t.Fatal("Send() to the closed backend error = nil")
+	}
+
+	if got := inFlight(); got != 0 {
+		t.Fatalf("in flight after a failure = %d, want 0", got)
+	}
+}
+
+func TestNewClientLoadBalancer_UnwrapRotates(t *testing.T) {
+	t.Parallel()
+
+	client := NewClientLoadBalancer(
+		[]string{"https://a.example.com", "https://b.example.com", "https://c.example.com"},
+		WithStrategy(RoundRobin()),
+	).Build()
+
+	balancer := client.(*ClientConfigBase).ConfigBaseURL.(*BalancedBaseURL)
+
+	picked := make([]string, 3)
+	for i := range picked {
+		req, err := client.GET("/").Unwrap()
+		if err != nil {
+			t.Fatalf("Unwrap() error = %v", err)
+		}
+
+		picked[i] = req.URL.Hostname()[:1]
+	}
+
+	if !slices.Equal(picked, []string{"a", "b", "c"}) {
+		t.Fatalf("picks = %v, want a round-robin rotation", picked)
+	}
+
+	for _, backend := range balancer.Backends() {
+		if got := backend.InFlight(); got != 0 {
+			t.Fatalf("%s in flight = %d, want 0", backend.URL().Host, got)
+		}
+	}
+}
+
+func TestNewClientLoadBalancer_ReleasesReplacedResponse(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("mai"))
+	}))
+	t.Cleanup(server.Close)
+
+	client := NewClientLoadBalancer([]string{server.URL, server.URL}, WithStrategy(LeastInFlight())).
+		Intercept().OnResponse(func(contracts.Response) (contracts.Response, error) {
+		// the original body is never closed
+		return NewResponse(&http.Response{StatusCode: http.StatusOK, Body: http.NoBody}), nil
+	}).
+		Build()
+
+	balancer := client.(*ClientConfigBase).ConfigBaseURL.(*BalancedBaseURL)
+
+	if _, err := client.GET("/").Send(); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	for _, backend := range balancer.Backends() {
+		if got := backend.InFlight(); got != 0 {
+			t.Fatalf("in flight = %d, want 0 once the response is replaced", got)
+		}
+	}
+}
@@ -2,6 +2,8 @@ package maigo
 
 import (
 	"net/url"
+	"slices"
+	"sync"
 	"sync/atomic"
 
 	"github.com/jeanmolossi/maigo/pkg/maigo/contracts"
@@ -14,9 +16,18 @@ type (
 	}
 
 	// BalancedBaseURL implements contracts.ConfigBaseURL interface and provides a load balancing.
+	// The backend of each request is chosen by its contracts.BalanceStrategy,
+	// round-robin by default.
 	BalancedBaseURL struct {
-		baseURLs       []*url.URL
-		currentBaseURL uint32
+		backends []contracts.Backend
+		strategy contracts.BalanceStrategy
+	}
+
+	// backend implements contracts.Backend, counting the requests in flight.
+	backend struct {
+		url      *url.URL
+		weight   int
+		inFlight atomic.Int64
 	}
 )
 
@@ -24,6 +35,7 @@ type (
 var (
 	_ contracts.ConfigBaseURL = (*DefaultBaseURL)(nil)
 	_ contracts.ConfigBaseURL = (*BalancedBaseURL)(nil)
+	_ contracts.Backend       = (*backend)(nil)
 )
 
 // BaseURL for DefaultBaseURL return the base URL.
@@ -31,20 +43,70 @@ func (d *DefaultBaseURL) BaseURL() *url.URL {
 	return d.baseURL
 }
 
-// BaseURL for BalancedBaseURL returns the next base URL in the list.
+// AcquireBaseURL implements contracts.ConfigBaseURL.
+func (d *DefaultBaseURL) AcquireBaseURL() (*url.URL, func()) {
+	return d.baseURL, func() {}
+}
+
+// BaseURL for BalancedBaseURL returns the next base URL chosen by the
+// strategy, without counting a request in flight on it.
 // It is safe for concurrent use and for zero or single URLs.
 func (b *BalancedBaseURL) BaseURL() *url.URL {
-	l := len(b.baseURLs)
-	switch l {
+	baseURL, release := b.AcquireBaseURL()
+	release()
+
+	return baseURL
+}
+
+// AcquireBaseURL implements contracts.ConfigBaseURL. It returns the base URL
+// chosen by the strategy, the request counting as in flight on it until
+// release is called. It is safe for concurrent use and for zero or single
+// URLs.
+func (b *BalancedBaseURL) AcquireBaseURL() (*url.URL, func()) {
+	selected := b.pick()
+	if selected == nil {
+		return nil, func() {}
+	}
+
+	selected.inFlight.Add(1)
+
+	return selected.url, sync.OnceFunc(func() { selected.inFlight.Add(-1) })
+}
+
+// Backends returns the backends of the balancer, in the order of their URLs.
+func (b *BalancedBaseURL) Backends() []contracts.Backend {
+	return slices.Clone(b.backends)
+}
+
+func (b *BalancedBaseURL) pick() *backend {
+	switch len(b.backends) {
 	case 0:
 		return nil
 	case 1:
-		return b.baseURLs[0]
+		return b.backends[0].(*backend)
 	}
 
-	idx := atomic.AddUint32(&b.currentBaseURL, 1) - 1
+	idx := b.strategy.Pick(b.backends)
+	if idx < 0 || idx >= len(b.backends) {
+		idx = 0
+	}
+
+	return b.backends[idx].(*backend)
+}
+
+// URL implements contracts.Backend.
+func (b *backend) URL() *url.URL {
+	return b.url
+}
+
+// Weight implements contracts.Backend.
+func (b *backend) Weight() int {
+	return b.weight
+}
 
-	return b.baseURLs[idx%uint32(l)]
+// InFlight implements contracts.Backend.
+func (b *backend) InFlight() int64 {
+	return b.inFlight.Load()
 }
 
 // newDefaultBaseURL initializes a new DefaultBaseURL with a given base URL.
@@ -54,9 +116,26 @@ func newDefaultBaseURL(baseURL *url.URL) *DefaultBaseURL {
 	}
 }
 
-// newBalancedBaseURL initializes a new BalancedBaseURL with a given base URLs.
-func newBalancedBaseURL(baseURLs []*url.URL) *BalancedBaseURL {
+// newBalancedBaseURL initializes a new BalancedBaseURL with a given base URLs,
+// weights, one per URL or none, and strategy.
+func newBalancedBaseURL(baseURLs []*url.URL, weights []int, strategy contracts.BalanceStrategy) *BalancedBaseURL {
+	backends := make([]contracts.Backend, len(baseURLs))
+
+	for i, baseURL := range baseURLs {
+		weight := 1
+		if i < len(weights) {
+			weight = weights[i]
+		}
+
+		backends[i] = &backend{url: baseURL, weight: weight}
+	}
+
+	if strategy == nil {
+		strategy = RoundRobin()
+	}
+
 	return &BalancedBaseURL{
-		baseURLs: baseURLs,
+		backends: backends,
+		strategy: strategy,
 	}
 }
@@ -44,7 +44,7 @@ func TestBalancedBaseURL_RoundRobin(t *testing.T) {
 		urls[i] = mustParse(t, r)
 	}
 
-	b := newBalancedBaseURL(urls)
+	b := newBalancedBaseURL(urls, nil, nil)
 
 	for i := 0; i < len(urls)*2; i++ {
 		want := raw[i%len(raw)]
@@ -70,7 +70,7 @@ func TestBalancedBaseURL_RoundRobin_Concurrent(t *testing.T) {
 		urls[i] = mustParse(t, r)
 	}
 
-	b := newBalancedBaseURL(urls)
+	b := newBalancedBaseURL(urls, nil, nil)
 
 	const workers = 300
 
@@ -131,7 +131,7 @@ func TestBalancedBaseURL_SingleURL(t *testing.T) {
 
 	u := mustParse(t, "https://only.com")
 
-	b := newBalancedBaseURL([]*url.URL{u})
+	b := newBalancedBaseURL([]*url.URL{u}, nil, nil)
 
 	for i := 0; i < 10; i++ {
 		if got := b.BaseURL(); got != u {
@@ -161,7 +161,7 @@ func TestBalancedBaseURL_SingleURL(t *testing.T) {
 func TestBalancedBaseURL_EmptyURLs(t *testing.T) {
 	t.Parallel()
 
-	b := newBalancedBaseURL(nil)
+	b := newBalancedBaseURL(nil, nil, nil)
 
 	for i := 0; i < 10; i++ {
 		if got := b.BaseURL(); got != nil {
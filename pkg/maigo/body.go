@@ -311,6 +311,14 @@ func (b *BufferedBody) Unwrap() (body io.Reader) {
 	return
 }
 
+// clone returns a buffered body holding a copy of the data.
+func (b *BufferedBody) clone() *BufferedBody {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	return &BufferedBody{buffer: bytes.NewBuffer(bytes.Clone(b.buffer.Bytes()))}
+}
+
 func newBufferedBody() *BufferedBody {
 	return &BufferedBody{
 		buffer: bytes.NewBuffer(nil),
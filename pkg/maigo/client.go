@@ -25,10 +25,13 @@ func (d *Client) Do(r *http.Request) (*http.Response, error) {
 
 // SetFollowRedirects implements contracts.HTTPClient.
 func (d *Client) SetFollowRedirects(follow bool) {
-	if !follow {
-		d.client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
-			return http.ErrUseLastResponse
-		}
+	if follow {
+		d.client.CheckRedirect = nil
+		return
+	}
+
+	d.client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
 	}
 }
 
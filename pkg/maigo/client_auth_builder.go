@@ -0,0 +1,77 @@
+package maigo
+
+import (
+	"fmt"
+
+	"github.com/jeanmolossi/maigo/pkg/maigo/contracts"
+	"github.com/jeanmolossi/maigo/pkg/maigo/header"
+)
+
+var _ contracts.BuilderAuth[contracts.ClientBuilder] = (*ClientAuthBuilder)(nil)
+
+type ClientAuthBuilder struct {
+	parent *ClientBuilder
+}
+
+func (b *ClientBuilder) Auth() contracts.BuilderAuth[contracts.ClientBuilder] {
+	return &ClientAuthBuilder{parent: b}
+}
+
+// Set implements contracts.BuilderAuth.
+func (c *ClientAuthBuilder) Set(value string) contracts.ClientBuilder {
+	auth, err := NewHeaderAuth(header.Authorization, value)
+	return c.apply(auth, err)
+}
+
+// BearerToken implements contracts.BuilderAuth.
+func (c *ClientAuthBuilder) BearerToken(token string) contracts.ClientBuilder {
+	auth, err := NewBearerAuth(token)
+	return c.apply(auth, err)
+}
+
+// BasicAuth implements contracts.BuilderAuth.
+func (c *ClientAuthBuilder) BasicAuth(user, pass string) contracts.ClientBuilder {
+	auth, err := NewBasicAuth(user, pass)
+	return c.apply(auth, err)
+}
+
+// APIKeyHeader implements contracts.BuilderAuth.
+func (c *ClientAuthBuilder) APIKeyHeader(key header.Type, value string) contracts.ClientBuilder {
+	auth, err := NewHeaderAuth(key, value)
+	return c.apply(auth, err)
+}
+
+// APIKeyQuery implements contracts.BuilderAuth.
+func (c *ClientAuthBuilder) APIKeyQuery(param, value string) contracts.ClientBuilder {
+	auth, err := NewQueryAuth(param, value)
+	return c.apply(auth, err)
+}
+
+// TokenSource implements contracts.BuilderAuth.
+func (c *ClientAuthBuilder) TokenSource(source contracts.TokenSource) contracts.ClientBuilder {
+	if source == nil {
+		return c.apply(nil, fmt.Errorf("%w: nil token source", ErrInvalidAuth))
+	}
+
+	return c.apply(NewTokenSourceAuth(source), nil)
+}
+
+// Custom implements contracts.BuilderAuth.
+func (c *ClientAuthBuilder) Custom(auth contracts.Authenticator) contracts.ClientBuilder {
+	if auth == nil {
+		return c.apply(nil, fmt.Errorf("%w: nil authenticator", ErrInvalidAuth))
+	}
+
+	return c.apply(auth, nil)
+}
+
+func (c *ClientAuthBuilder) apply(auth contracts.Authenticator, err error) contracts.ClientBuilder {
+	if err != nil {
+		c.parent.client.Validations().Add(err)
+		return c.parent
+	}
+
+	c.parent.client.SetAuthenticator(auth)
+
+	return c.parent
+}
@@ -14,9 +14,19 @@ func NewClient(baseURL string) *ClientBuilder {
 	}
 }
 
-func NewClientLoadBalancer(baseURLs []string) *ClientBuilder {
+// NewClientLoadBalancer creates a client spreading its requests over
+// baseURLs, round-robin unless another strategy is chosen with WithStrategy.
+//
+// Example:
+//
+//	client := maigo.NewClientLoadBalancer(
+//	        []string{"https://a.example.com", "https://b.example.com"},
+//	        maigo.WithStrategy(maigo.WeightedRoundRobin()),
+//	        maigo.WithWeights(3, 1),
+//	).Build()
+func NewClientLoadBalancer(baseURLs []string, opts ...BalancerOption) *ClientBuilder {
 	return &ClientBuilder{
-		client: newBalancedClientConfigBase(baseURLs),
+		client: newBalancedClientConfigBase(baseURLs, opts...),
 	}
 }
 
@@ -28,6 +38,12 @@ func DefaultClientCompat(baseURL string) contracts.ClientCompat {
 	return NewClient(baseURL).client
 }
 
+// ExpectSuccess implements contracts.ClientBuilder.
+func (b *ClientBuilder) ExpectSuccess() contracts.ClientBuilder {
+	b.client.SetExpectSuccess(true)
+	return b
+}
+
 // Build implements contracts.Builder.
 func (b *ClientBuilder) Build() contracts.ClientHTTPMethods {
 	return b.client
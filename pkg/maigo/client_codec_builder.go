@@ -0,0 +1,30 @@
+package maigo
+
+import (
+	"fmt"
+
+	"github.com/jeanmolossi/maigo/pkg/maigo/contracts"
+	"github.com/jeanmolossi/maigo/pkg/maigo/mime"
+)
+
+var _ contracts.BuilderCodec[contracts.ClientBuilder] = (*ClientCodecBuilder)(nil)
+
+type ClientCodecBuilder struct {
+	parent *ClientBuilder
+}
+
+func (b *ClientBuilder) Codec() contracts.BuilderCodec[contracts.ClientBuilder] {
+	return &ClientCodecBuilder{parent: b}
+}
+
+// Register implements contracts.BuilderCodec.
+func (c *ClientCodecBuilder) Register(mediaType mime.Type, codec contracts.Codec) contracts.ClientBuilder {
+	if codec == nil {
+		c.parent.client.Validations().Add(fmt.Errorf("%w: nil codec for %s", ErrInvalidCodec, mediaType))
+		return c.parent
+	}
+
+	c.parent.client.Codecs().Register(mediaType, codec)
+
+	return c.parent
+}
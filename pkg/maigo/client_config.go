@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"sync/atomic"
 
+	"github.com/jeanmolossi/maigo/pkg/maigo/codec"
 	"github.com/jeanmolossi/maigo/pkg/maigo/contracts"
 	"github.com/jeanmolossi/maigo/pkg/maigo/method"
 )
@@ -21,9 +23,20 @@ type ClientConfigBase struct {
 	httpClient  contracts.HTTPClient
 	httpHeader  contracts.Header
 	httpCookie  contracts.Cookies
+	auth        contracts.Authenticator
+	mediaType   contracts.MediaTypeMode
+	codecs      contracts.Codecs
 	validations contracts.Validations
+	// expectSuccess reports non-2xx responses as errors.
+	expectSuccess bool
+	// tokenSources caches the token sources given to requests.
+	tokenSources *tokenSourceCaches
+	// sharedTransport is the transport shared with the clients derived
+	// through With, cloned before being modified.
+	sharedTransport atomic.Pointer[http.Transport]
 
 	contracts.ConfigBaseURL
+	contracts.ConfigInterceptors
 }
 
 // CONNECT implements contracts.ClientHTTPMethods.
@@ -107,6 +120,41 @@ func (c *ClientConfigBase) SetHttpClient(httpc contracts.HTTPClient) {
 	c.httpClient = httpc
 }
 
+// Authenticator implements contracts.ConfigAuth.
+func (c *ClientConfigBase) Authenticator() contracts.Authenticator {
+	return c.auth
+}
+
+// SetAuthenticator implements contracts.ConfigAuth.
+func (c *ClientConfigBase) SetAuthenticator(auth contracts.Authenticator) {
+	c.auth = auth
+}
+
+// MediaTypeMode implements contracts.ConfigMediaType.
+func (c *ClientConfigBase) MediaTypeMode() contracts.MediaTypeMode {
+	return c.mediaType
+}
+
+// SetMediaTypeMode implements contracts.ConfigMediaType.
+func (c *ClientConfigBase) SetMediaTypeMode(mode contracts.MediaTypeMode) {
+	c.mediaType = mode
+}
+
+// Codecs implements contracts.ConfigCodecs.
+func (c *ClientConfigBase) Codecs() contracts.Codecs {
+	return c.codecs
+}
+
+// ExpectsSuccess implements contracts.ConfigExpectSuccess.
+func (c *ClientConfigBase) ExpectsSuccess() bool {
+	return c.expectSuccess
+}
+
+// SetExpectSuccess implements contracts.ConfigExpectSuccess.
+func (c *ClientConfigBase) SetExpectSuccess(expect bool) {
+	c.expectSuccess = expect
+}
+
 // Validations implements contracts.ClientConfig.
 func (c *ClientConfigBase) Validations() contracts.Validations {
 	return c.validations
@@ -128,15 +176,26 @@ func newClientConfigBase(baseURL string) *ClientConfigBase {
 		httpClient:    newDefaultHTTPClient(),
 		httpHeader:    newDefaultHTTPHeader(),
 		httpCookie:    newDefaultHTTPCookies(),
+		codecs:        codec.Default(),
 		validations:   newDefaultValidations(validations),
+		tokenSources:  newTokenSourceCaches(),
 		ConfigBaseURL: newDefaultBaseURL(parsedURL),
+
+		ConfigInterceptors: newDefaultInterceptors(),
 	}
 }
 
-func newBalancedClientConfigBase(baseURLs []string) *ClientConfigBase {
-	var validations []error
+func newBalancedClientConfigBase(baseURLs []string, opts ...BalancerOption) *ClientConfigBase {
+	var config balancerConfig
+
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	validations := config.validate(len(baseURLs))
 
 	parsedURLs := make([]*url.URL, 0, len(baseURLs)) // pre-alloc cap like baseURLs
+	weights := make([]int, 0, len(config.weights))
 
 	for index, baseURL := range baseURLs {
 		if baseURL == "" {
@@ -144,6 +203,10 @@ func newBalancedClientConfigBase(baseURLs []string) *ClientConfigBase {
 			continue
 		}
 
+		if index < len(config.weights) {
+			weights = append(weights, config.weights[index])
+		}
+
 		parsedURL, err := url.Parse(baseURL)
 		if err != nil {
 			validations = append(validations, errors.Join(ErrParseURL, err))
@@ -160,7 +223,11 @@ func newBalancedClientConfigBase(baseURLs []string) *ClientConfigBase {
 		httpClient:    newDefaultHTTPClient(),
 		httpHeader:    newDefaultHTTPHeader(),
 		httpCookie:    newDefaultHTTPCookies(),
+		codecs:        codec.Default(),
 		validations:   newDefaultValidations(validations),
-		ConfigBaseURL: newBalancedBaseURL(parsedURLs),
+		tokenSources:  newTokenSourceCaches(),
+		ConfigBaseURL: newBalancedBaseURL(parsedURLs, weights, config.strategy),
+
+		ConfigInterceptors: newDefaultInterceptors(),
 	}
 }
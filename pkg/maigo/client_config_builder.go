@@ -3,10 +3,13 @@ package maigo
 import (
 	"crypto/tls"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/url"
 	"time"
 
+	"github.com/jeanmolossi/maigo/pkg/httpx"
+	"github.com/jeanmolossi/maigo/pkg/httpx/compression"
 	"github.com/jeanmolossi/maigo/pkg/maigo/contracts"
 )
 
@@ -21,25 +24,63 @@ func (b *ClientBuilder) Config() contracts.BuilderHTTPClientConfig[contracts.Cli
 }
 
 // SetCustomHTTPClient implements contracts.BuilderHTTPClientConfig.
+//
+// A *Client, such as the one of another MaiGo client, is copied before the
+// cookie jar and middlewares in use are carried over to it. Other clients
+// are used, and changed, as they are.
 func (c *ClientConfigBuilder) SetCustomHTTPClient(httpClient contracts.HTTPClient) contracts.ClientBuilder {
+	if custom, ok := httpClient.(*Client); ok && custom.client != nil {
+		clone := *custom.client
+		httpClient = &Client{client: &clone}
+	}
+
+	current := c.parent.client.HttpClient()
+	chain, hasChain := current.Transport().(*httpx.Chain)
+
 	c.parent.client.SetHttpClient(httpClient)
+
+	// keep the cookie jar already in use, unless the new client has one
+	if jar := cookieJar(current); jar != nil {
+		if compat, ok := httpClient.(contracts.HTTPClientCompat); ok && compat.Unwrap() != nil && compat.Unwrap().Jar == nil {
+			compat.Unwrap().Jar = jar
+		}
+	}
+
+	// keep the middlewares already in use over the new client's transport
+	if hasChain {
+		if _, ok := httpClient.Transport().(*httpx.Chain); !ok {
+			chain.SetBase(httpClient.Transport())
+			httpClient.SetTransport(chain)
+		}
+	}
+
 	return c.parent
 }
 
+// cookieJar returns the cookie jar of httpClient, if it exposes one.
+func cookieJar(httpClient contracts.HTTPClient) http.CookieJar {
+	compat, ok := httpClient.(contracts.HTTPClientCompat)
+	if !ok || compat.Unwrap() == nil {
+		return nil
+	}
+
+	return compat.Unwrap().Jar
+}
+
 // SetCustomTransport implements contracts.BuilderHTTPClientConfig.
 func (c *ClientConfigBuilder) SetCustomTransport(transport http.RoundTripper) contracts.ClientBuilder {
-	c.parent.client.HttpClient().SetTransport(transport)
+	c.parent.setBaseTransport(transport)
 	return c.parent
 }
 
 // SetTLSConfig implements contracts.BuilderHTTPClientConfig.
 func (c *ClientConfigBuilder) SetTLSConfig(tlsConfig *tls.Config) contracts.ClientBuilder {
-	if transport, ok := c.parent.client.HttpClient().Transport().(*http.Transport); ok {
+	if transport, ok := c.parent.ownTransport(); ok {
 		transport.TLSClientConfig = tlsConfig
 		return c.parent
 	}
 
-	c.parent.client.HttpClient().SetTransport(&http.Transport{
+	c.parent.setBaseTransport(&http.Transport{
 		TLSClientConfig: tlsConfig,
 	})
 
@@ -63,10 +104,10 @@ func (c *ClientConfigBuilder) SetProxy(proxyURL string) contracts.ClientBuilder
 		return c.parent
 	}
 
-	if transport, ok := c.parent.client.HttpClient().Transport().(*http.Transport); ok {
+	if transport, ok := c.parent.ownTransport(); ok {
 		transport.Proxy = http.ProxyURL(parsedURL)
 	} else {
-		c.parent.client.HttpClient().SetTransport(&http.Transport{
+		c.parent.setBaseTransport(&http.Transport{
 			Proxy: http.ProxyURL(parsedURL),
 		})
 	}
@@ -74,6 +115,24 @@ func (c *ClientConfigBuilder) SetProxy(proxyURL string) contracts.ClientBuilder
 	return c.parent
 }
 
+// SetMediaTypeMode implements contracts.BuilderHTTPClientConfig.
+func (c *ClientConfigBuilder) SetMediaTypeMode(mode contracts.MediaTypeMode) contracts.ClientBuilder {
+	c.parent.client.SetMediaTypeMode(mode)
+	return c.parent
+}
+
+// SetDecompression implements contracts.BuilderHTTPClientConfig.
+func (c *ClientConfigBuilder) SetDecompression(encodings ...compression.Encoding) contracts.ClientBuilder {
+	for _, encoding := range encodings {
+		if !encoding.Supported() {
+			c.parent.client.Validations().Add(fmt.Errorf("%w: %q", compression.ErrUnsupportedEncoding, encoding))
+			return c.parent
+		}
+	}
+
+	return c.parent.Use(compression.WithDecompression(compression.DecompressionConfig{Encodings: encodings}))
+}
+
 // SetTimeout implements contracts.BuilderHTTPClientConfig.
 func (c *ClientConfigBuilder) SetTimeout(duration time.Duration) contracts.ClientBuilder {
 	c.parent.client.HttpClient().SetTimeout(duration)
@@ -53,3 +53,23 @@ func TestClientConfigBuilder_SetTLSConfig_KeepsExistingTransport(t *testing.T) {
 		t.Fatalf("TLSClientConfig = %v, want %v", transport.TLSClientConfig, tlsConfig)
 	}
 }
+
+func TestClientConfigBuilder_SetCustomHTTPClientCopiesClient(t *testing.T) {
+	t.Parallel()
+
+	source := DefaultClientCompat("https://example.com").HttpClient()
+	original := source.(contracts.HTTPClientCompat).Unwrap()
+
+	client := NewClient("https://example.com").
+		UseNamed("noop", func(next http.RoundTripper) http.RoundTripper { return next }).
+		Config().SetCustomHTTPClient(source).
+		Build()
+
+	if client.(contracts.ClientCompat).HttpClient() == source {
+		t.Fatalf("HttpClient() is the client given to SetCustomHTTPClient")
+	}
+
+	if original.Transport != nil || original.Jar != nil {
+		t.Fatalf("given client was modified: transport=%T jar=%v", original.Transport, original.Jar)
+	}
+}
@@ -1,7 +1,9 @@
 package maigo
 
 import (
+	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/jeanmolossi/maigo/pkg/maigo/contracts"
 )
@@ -16,8 +18,40 @@ func (b *ClientBuilder) Cookie() contracts.BuilderCookie[contracts.ClientBuilder
 	return &ClientCookieBuilder{parent: b}
 }
 
-// Add implements contracts.BuilderCookie.
+// Add implements contracts.BuilderCookie. The cookie is only sent to the
+// hosts and paths matching its Domain and Path, over https when it is
+// Secure, until it expires. Cookies with inconsistent attributes fail the
+// client validation.
 func (c *ClientCookieBuilder) Add(cookie *http.Cookie) contracts.ClientBuilder {
+	if err := validateCookie(cookie); err != nil {
+		c.parent.client.Validations().Add(err)
+		return c.parent
+	}
+
+	// Max-Age counts from now, not from each request
+	if cookie.MaxAge > 0 {
+		clone := cloneCookie(cookie)
+		clone.Expires = time.Now().Add(time.Duration(cookie.MaxAge) * time.Second)
+		cookie = clone
+	}
+
 	c.parent.client.Cookies().Add(cookie)
+
+	return c.parent
+}
+
+// Jar implements contracts.BuilderCookie. It requires an HTTP client exposing
+// its *http.Client, as the default one does.
+func (c *ClientCookieBuilder) Jar(jar http.CookieJar) contracts.ClientBuilder {
+	httpClient := c.parent.client.HttpClient()
+
+	compat, ok := httpClient.(contracts.HTTPClientCompat)
+	if !ok || compat.Unwrap() == nil {
+		c.parent.client.Validations().Add(fmt.Errorf("%w: %T does not expose an *http.Client", ErrCookieJar, httpClient))
+		return c.parent
+	}
+
+	compat.Unwrap().Jar = jar
+
 	return c.parent
 }
@@ -63,3 +63,16 @@ func (c *ClientHeaderBuilder) SetAll(headers map[header.Type]string) contracts.C
 
 	return c.parent
 }
+
+// Append implements contracts.BuilderHeader. Client headers have nothing to
+// follow, so it is the same as Add.
+func (c *ClientHeaderBuilder) Append(key header.Type, value string) contracts.ClientBuilder {
+	appendHeader(c.parent.client.Header(), key, value)
+	return c.parent
+}
+
+// Remove implements contracts.BuilderHeader.
+func (c *ClientHeaderBuilder) Remove(key header.Type) contracts.ClientBuilder {
+	removeHeader(c.parent.client.Header(), key)
+	return c.parent
+}
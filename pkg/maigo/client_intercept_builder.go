@@ -0,0 +1,41 @@
+package maigo
+
+import (
+	"fmt"
+
+	"github.com/jeanmolossi/maigo/pkg/maigo/contracts"
+)
+
+var _ contracts.BuilderInterceptor[contracts.ClientBuilder] = (*ClientInterceptBuilder)(nil)
+
+type ClientInterceptBuilder struct {
+	parent *ClientBuilder
+}
+
+func (b *ClientBuilder) Intercept() contracts.BuilderInterceptor[contracts.ClientBuilder] {
+	return &ClientInterceptBuilder{parent: b}
+}
+
+// OnRequest implements contracts.BuilderInterceptor.
+func (c *ClientInterceptBuilder) OnRequest(interceptor contracts.RequestInterceptor) contracts.ClientBuilder {
+	if interceptor == nil {
+		c.parent.client.Validations().Add(fmt.Errorf("%w: nil request interceptor", ErrInvalidInterceptor))
+		return c.parent
+	}
+
+	c.parent.client.AddRequestInterceptor(interceptor)
+
+	return c.parent
+}
+
+// OnResponse implements contracts.BuilderInterceptor.
+func (c *ClientInterceptBuilder) OnResponse(interceptor contracts.ResponseInterceptor) contracts.ClientBuilder {
+	if interceptor == nil {
+		c.parent.client.Validations().Add(fmt.Errorf("%w: nil response interceptor", ErrInvalidInterceptor))
+		return c.parent
+	}
+
+	c.parent.client.AddResponseInterceptor(interceptor)
+
+	return c.parent
+}
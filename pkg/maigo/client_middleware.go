@@ -0,0 +1,63 @@
+package maigo
+
+import (
+	"net/http"
+
+	"github.com/jeanmolossi/maigo/pkg/httpx"
+	"github.com/jeanmolossi/maigo/pkg/maigo/contracts"
+)
+
+// Use implements contracts.ClientBuilder.
+func (b *ClientBuilder) Use(middlewares ...httpx.ChainedRoundTripper) contracts.ClientBuilder {
+	chain := b.chain()
+
+	for _, middleware := range middlewares {
+		chain.Use(httpx.Middleware{Wrap: middleware})
+	}
+
+	return b
+}
+
+// UseNamed implements contracts.ClientBuilder.
+func (b *ClientBuilder) UseNamed(name string, middleware httpx.ChainedRoundTripper) contracts.ClientBuilder {
+	b.chain().Use(httpx.Named(name, middleware))
+	return b
+}
+
+// chain returns the middleware chain of the client, installing one over the
+// current transport on first use.
+func (b *ClientBuilder) chain() *httpx.Chain {
+	httpClient := b.client.HttpClient()
+
+	if chain, ok := httpClient.Transport().(*httpx.Chain); ok {
+		return chain
+	}
+
+	chain := httpx.NewChain(httpClient.Transport())
+	httpClient.SetTransport(chain)
+
+	return chain
+}
+
+// baseTransport returns the transport beneath the middleware chain, or the
+// client transport when no middleware is in use.
+func (b *ClientBuilder) baseTransport() http.RoundTripper {
+	transport := b.client.HttpClient().Transport()
+
+	if chain, ok := transport.(*httpx.Chain); ok {
+		return chain.Base()
+	}
+
+	return transport
+}
+
+// setBaseTransport replaces the transport beneath the middleware chain, or
+// the client transport when no middleware is in use.
+func (b *ClientBuilder) setBaseTransport(transport http.RoundTripper) {
+	if chain, ok := b.client.HttpClient().Transport().(*httpx.Chain); ok {
+		chain.SetBase(transport)
+		return
+	}
+
+	b.client.HttpClient().SetTransport(transport)
+}
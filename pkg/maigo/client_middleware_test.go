@@ -0,0 +1,81 @@
+package maigo
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/jeanmolossi/maigo/pkg/httpx"
+	"github.com/jeanmolossi/maigo/pkg/httpx/retry"
+	"github.com/jeanmolossi/maigo/pkg/maigo/contracts"
+)
+
+func TestClientBuilder_UseKeepsBaseTransportSettings(t *testing.T) {
+	t.Parallel()
+
+	tlsConfig := &tls.Config{ServerName: "example.com"}
+
+	// middlewares first, transport settings last
+	builder := NewClient("https://example.com")
+	builder.Use(retry.WithRetry(retry.RetryConfig{MaxAttempts: 2}))
+	builder.UseNamed("noop", func(next http.RoundTripper) http.RoundTripper { return next })
+	builder.Config().SetTLSConfig(tlsConfig)
+	builder.Config().SetProxy("http://proxy.local:3128")
+
+	client := builder.Build().(contracts.ClientCompat)
+
+	chain, ok := client.HttpClient().Transport().(*httpx.Chain)
+	if !ok {
+		t.Fatalf("transport is not *httpx.Chain: %T", client.HttpClient().Transport())
+	}
+
+	if names := chain.Names(); !slices.Equal(names, []string{"retry.WithRetry", "noop"}) {
+		t.Fatalf("Names() = %v, want [retry.WithRetry noop]", names)
+	}
+
+	base, ok := chain.Base().(*http.Transport)
+	if !ok {
+		t.Fatalf("base transport is not *http.Transport: %T", chain.Base())
+	}
+
+	if base.TLSClientConfig != tlsConfig || base.Proxy == nil {
+		t.Fatalf("base transport lost its settings: tls=%v proxy=%v", base.TLSClientConfig, base.Proxy != nil)
+	}
+}
+
+func TestClientBuilder_UseWrapsCustomTransport(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Seen", r.Header.Get("X-Middleware"))
+	}))
+	t.Cleanup(server.Close)
+
+	transport := &http.Transport{}
+
+	client := NewClient(server.URL).
+		Config().SetCustomTransport(transport).
+		UseNamed("tag", func(next http.RoundTripper) http.RoundTripper {
+			return httpx.RoundTripperFn(func(r *http.Request) (*http.Response, error) {
+				r.Header.Set("X-Middleware", "on")
+				return next.RoundTrip(r)
+			})
+		}).
+		Build()
+
+	resp, err := client.GET("/").Send()
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	if got := resp.Header().Get("X-Seen"); got != "on" {
+		t.Fatalf("X-Seen = %q, want %q", got, "on")
+	}
+
+	chain := client.(contracts.ClientCompat).HttpClient().Transport().(*httpx.Chain)
+	if chain.Base() != transport {
+		t.Fatalf("Base() = %p, want %p", chain.Base(), transport)
+	}
+}
@@ -0,0 +1,87 @@
+package maigo
+
+import (
+	"net/http"
+	"slices"
+
+	"github.com/jeanmolossi/maigo/pkg/httpx"
+	"github.com/jeanmolossi/maigo/pkg/maigo/codec"
+	"github.com/jeanmolossi/maigo/pkg/maigo/contracts"
+)
+
+// With implements contracts.ClientHTTPMethods.
+//
+// The child gets its own *http.Client over the same transport, so its
+// timeout and redirect policy can be changed on their own, and its own
+// middleware chain, so middlewares added to it are not seen by the parent.
+// TLS and proxy settings of either client clone the shared *http.Transport
+// before changing it, so that client then pools its connections apart. The
+// cookie jar is shared, so the child carries on the session of the parent.
+// A custom HTTP client that is not a *Client is shared as is.
+func (c *ClientConfigBase) With() contracts.ClientBuilder {
+	child := &ClientConfigBase{
+		httpClient:    c.deriveHTTPClient(),
+		httpHeader:    cloneHeader(c.httpHeader),
+		httpCookie:    &Cookies{cookies: c.httpCookie.Unwrap()},
+		auth:          c.auth,
+		mediaType:     c.mediaType,
+		codecs:        c.codecs,
+		validations:   newDefaultValidations(slices.Clone(c.validations.Unwrap())),
+		expectSuccess: c.expectSuccess,
+		tokenSources:  c.tokenSources,
+		ConfigBaseURL: c.ConfigBaseURL,
+
+		ConfigInterceptors: &Interceptors{
+			request:  slices.Clone(c.RequestInterceptors()),
+			response: slices.Clone(c.ResponseInterceptors()),
+		},
+	}
+
+	if registry, ok := c.codecs.(*codec.Registry); ok {
+		child.codecs = registry.Clone()
+	}
+
+	builder := &ClientBuilder{client: child}
+
+	// both clients clone the transport before changing it
+	if transport, ok := builder.baseTransport().(*http.Transport); ok {
+		c.sharedTransport.Store(transport)
+		child.sharedTransport.Store(transport)
+	}
+
+	return builder
+}
+
+// deriveHTTPClient copies the HTTP client, layering a copy of its middleware
+// chain over the same base transport.
+func (c *ClientConfigBase) deriveHTTPClient() contracts.HTTPClient {
+	httpClient, ok := c.httpClient.(*Client)
+	if !ok {
+		return c.httpClient
+	}
+
+	clone := *httpClient.client
+
+	if chain, ok := clone.Transport.(*httpx.Chain); ok {
+		clone.Transport = httpx.NewChain(chain.Base(), chain.Middlewares()...)
+	}
+
+	return &Client{client: &clone}
+}
+
+// ownTransport returns the *http.Transport beneath the middleware chain,
+// cloning it first when it is shared with a client derived through With,
+// or with the client this one was derived from.
+func (b *ClientBuilder) ownTransport() (*http.Transport, bool) {
+	transport, ok := b.baseTransport().(*http.Transport)
+	if !ok {
+		return nil, false
+	}
+
+	if client, ok := b.client.(*ClientConfigBase); ok && client.sharedTransport.CompareAndSwap(transport, nil) {
+		transport = transport.Clone()
+		b.setBaseTransport(transport)
+	}
+
+	return transport, true
+}
@@ -0,0 +1,173 @@
+package maigo
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/jeanmolossi/maigo/pkg/httpx"
+	"github.com/jeanmolossi/maigo/pkg/maigo/contracts"
+	"github.com/jeanmolossi/maigo/pkg/maigo/header"
+)
+
+func TestClientWith_InheritsWithoutMutatingParent(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Seen-Team", r.Header.Get("X-Team"))
+		w.Header().Set("X-Seen-Extra", r.Header.Get("X-Extra"))
+		w.Header().Set("X-Seen-Auth", r.Header.Get("Authorization"))
+		w.Header().Set("X-Seen-Tags", r.Header.Get("X-Tags"))
+
+		var cookies []string
+		for _, cookie := range r.Cookies() {
+			cookies = append(cookies, cookie.Name)
+		}
+
+		w.Header()["X-Seen-Cookies"] = cookies
+	}))
+	t.Cleanup(server.Close)
+
+	tag := func(name string) httpx.ChainedRoundTripper {
+		return func(next http.RoundTripper) http.RoundTripper {
+			return httpx.RoundTripperFn(func(r *http.Request) (*http.Response, error) {
+				r.Header.Add("X-Tags", name)
+				return next.RoundTrip(r)
+			})
+		}
+	}
+
+	parent := NewClient(server.URL).
+		Header().Set("X-Team", "core").
+		Cookie().Add(&http.Cookie{Name: "session", Value: "abc"}).
+		Auth().BearerToken("parent").
+		UseNamed("parent", tag("parent")).
+		Config().SetTimeout(time.Second).
+		Build()
+
+	child := parent.With().
+		Header().Set("X-Team", "child").
+		Header().Add("X-Extra", "on").
+		Cookie().Add(&http.Cookie{Name: "tenant", Value: "42"}).
+		UseNamed("child", tag("child")).
+		Config().SetTimeout(time.Minute).
+		Build()
+
+	childResp, err := child.GET("/").Send()
+	if err != nil {
+		t.Fatalf("child Send() error = %v", err)
+	}
+
+	parentResp, err := parent.GET("/").Send()
+	if err != nil {
+		t.Fatalf("parent Send() error = %v", err)
+	}
+
+	tests := []struct {
+		name string
+		resp contracts.Response
+		key  string
+		want []string
+	}{
+		{name: "parent header", resp: parentResp, key: "X-Seen-Team", want: []string{"core"}},
+		{name: "parent extra", resp: parentResp, key: "X-Seen-Extra", want: []string{""}},
+		{name: "parent auth", resp: parentResp, key: "X-Seen-Auth", want: []string{"Bearer parent"}},
+		{name: "parent middlewares", resp: parentResp, key: "X-Seen-Tags", want: []string{"parent"}},
+		{name: "parent cookies", resp: parentResp, key: "X-Seen-Cookies", want: []string{"session"}},
+		{name: "child header", resp: childResp, key: "X-Seen-Team", want: []string{"child"}},
+		{name: "child extra", resp: childResp, key: "X-Seen-Extra", want: []string{"on"}},
+		{name: "child auth", resp: childResp, key: "X-Seen-Auth", want: []string{"Bearer parent"}},
+		{name: "child middlewares", resp: childResp, key: "X-Seen-Tags", want: []string{"parent"}},
+		{name: "child cookies", resp: childResp, key: "X-Seen-Cookies", want: []string{"session", "tenant"}},
+	}
+
+	for _, tt := range tests {
+		got := tt.resp.Raw().Header.Values(tt.key)
+		if len(got) == 0 {
+			got = []string{""}
+		}
+
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%s: %s = %q, want %q", tt.name, tt.key, got, tt.want)
+		}
+	}
+
+	parentChain := parent.(contracts.ClientCompat).HttpClient().Transport().(*httpx.Chain)
+	if names := parentChain.Names(); !slices.Equal(names, []string{"parent"}) {
+		t.Errorf("parent Names() = %v, want [parent]", names)
+	}
+
+	childChain := child.(contracts.ClientCompat).HttpClient().Transport().(*httpx.Chain)
+	if names := childChain.Names(); !slices.Equal(names, []string{"parent", "child"}) {
+		t.Errorf("child Names() = %v, want [parent child]", names)
+	}
+
+	if got := parent.(contracts.ClientCompat).HttpClient().Timeout(); got != time.Second {
+		t.Errorf("parent Timeout() = %v, want %v", got, time.Second)
+	}
+}
+
+func TestClientWith_SharesTransport(t *testing.T) {
+	t.Parallel()
+
+	transport := &http.Transport{}
+
+	parent := NewClient("https://example.com").
+		Config().SetCustomTransport(transport).
+		Build()
+
+	child := parent.With().Header().Set(header.Accept, "application/json").Build()
+
+	if got := child.(contracts.ClientCompat).HttpClient().Transport(); got != transport {
+		t.Fatalf("child Transport() = %p, want the parent transport %p", got, transport)
+	}
+
+	tlsConfig := &tls.Config{ServerName: "internal.example.com"}
+
+	secured := parent.With().Config().SetTLSConfig(tlsConfig).Build()
+
+	got, ok := secured.(contracts.ClientCompat).HttpClient().Transport().(*http.Transport)
+	if !ok || got == transport {
+		t.Fatalf("child with TLS settings shares the parent transport")
+	}
+
+	if got.TLSClientConfig != tlsConfig {
+		t.Fatalf("child TLSClientConfig = %v, want %v", got.TLSClientConfig, tlsConfig)
+	}
+
+	// cloning sets up HTTP/2 on the parent, which may allocate a tls.Config
+	if parentTLS := transport.TLSClientConfig; parentTLS != nil && parentTLS.ServerName != "" {
+		t.Fatalf("parent transport was modified: %v", transport.TLSClientConfig)
+	}
+}
+
+func TestClientWith_ParentChangesDoNotReachChild(t *testing.T) {
+	t.Parallel()
+
+	transport := &http.Transport{}
+
+	parentBuilder := NewClient("https://example.com").
+		UseNamed("parent", func(next http.RoundTripper) http.RoundTripper { return next })
+	parentBuilder.Config().SetCustomTransport(transport)
+
+	child := parentBuilder.Build().With().Build()
+
+	parentBuilder.UseNamed("later", func(next http.RoundTripper) http.RoundTripper { return next })
+	parentBuilder.Config().SetTLSConfig(&tls.Config{ServerName: "parent.example.com"})
+
+	chain := child.(contracts.ClientCompat).HttpClient().Transport().(*httpx.Chain)
+	if names := chain.Names(); !slices.Equal(names, []string{"parent"}) {
+		t.Fatalf("child Names() = %v, want [parent]", names)
+	}
+
+	if chain.Base() != transport {
+		t.Fatalf("child base transport = %p, want %p", chain.Base(), transport)
+	}
+
+	if tlsConfig := transport.TLSClientConfig; tlsConfig != nil && tlsConfig.ServerName != "" {
+		t.Fatalf("shared transport was modified by the parent: %v", tlsConfig.ServerName)
+	}
+}
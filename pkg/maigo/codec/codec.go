@@ -0,0 +1,149 @@
+package codec
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/jeanmolossi/maigo/pkg/maigo/contracts"
+	"github.com/vmihailenco/msgpack/v5"
+	"google.golang.org/protobuf/proto"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	_ contracts.Codec = JSON{}
+	_ contracts.Codec = XML{}
+	_ contracts.Codec = YAML{}
+	_ contracts.Codec = MsgPack{}
+	_ contracts.Codec = CBOR{}
+	_ contracts.Codec = Protobuf{}
+)
+
+// JSON encodes and decodes application/json bodies with encoding/json.
+type JSON struct {
+	// DisallowUnknownFields fails decoding when an object has a key that
+	// matches no field of the destination struct.
+	DisallowUnknownFields bool
+	// UseNumber decodes numbers into an interface{} as json.Number instead
+	// of float64.
+	UseNumber bool
+}
+
+// Encode implements contracts.Codec.
+func (c JSON) Encode(w io.Writer, v any) error {
+	return json.NewEncoder(w).Encode(v)
+}
+
+// Decode implements contracts.Codec.
+func (c JSON) Decode(r io.Reader, v any) error {
+	decoder := json.NewDecoder(r)
+
+	if c.DisallowUnknownFields {
+		decoder.DisallowUnknownFields()
+	}
+
+	if c.UseNumber {
+		decoder.UseNumber()
+	}
+
+	return decoder.Decode(v)
+}
+
+// XML encodes and decodes application/xml bodies with encoding/xml.
+type XML struct{}
+
+// Encode implements contracts.Codec.
+func (XML) Encode(w io.Writer, v any) error {
+	return xml.NewEncoder(w).Encode(v)
+}
+
+// Decode implements contracts.Codec.
+func (XML) Decode(r io.Reader, v any) error {
+	return xml.NewDecoder(r).Decode(v)
+}
+
+// YAML encodes and decodes application/yaml bodies with gopkg.in/yaml.v3.
+type YAML struct{}
+
+// Encode implements contracts.Codec.
+func (YAML) Encode(w io.Writer, v any) error {
+	encoder := yaml.NewEncoder(w)
+
+	if err := encoder.Encode(v); err != nil {
+		return err
+	}
+
+	return encoder.Close()
+}
+
+// Decode implements contracts.Codec.
+func (YAML) Decode(r io.Reader, v any) error {
+	return yaml.NewDecoder(r).Decode(v)
+}
+
+// MsgPack encodes and decodes application/msgpack bodies with
+// github.com/vmihailenco/msgpack/v5.
+type MsgPack struct{}
+
+// Encode implements contracts.Codec.
+func (MsgPack) Encode(w io.Writer, v any) error {
+	return msgpack.NewEncoder(w).Encode(v)
+}
+
+// Decode implements contracts.Codec.
+func (MsgPack) Decode(r io.Reader, v any) error {
+	return msgpack.NewDecoder(r).Decode(v)
+}
+
+// CBOR encodes and decodes application/cbor bodies with
+// github.com/fxamacker/cbor/v2.
+type CBOR struct{}
+
+// Encode implements contracts.Codec.
+func (CBOR) Encode(w io.Writer, v any) error {
+	return cbor.NewEncoder(w).Encode(v)
+}
+
+// Decode implements contracts.Codec.
+func (CBOR) Decode(r io.Reader, v any) error {
+	return cbor.NewDecoder(r).Decode(v)
+}
+
+// Protobuf encodes and decodes application/x-protobuf bodies. Values must be
+// proto.Message implementations.
+type Protobuf struct{}
+
+// Encode implements contracts.Codec.
+func (Protobuf) Encode(w io.Writer, v any) error {
+	message, ok := v.(proto.Message)
+	if !ok {
+		return fmt.Errorf("protobuf: %T is not a proto.Message", v)
+	}
+
+	data, err := proto.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(data)
+
+	return err
+}
+
+// Decode implements contracts.Codec.
+func (Protobuf) Decode(r io.Reader, v any) error {
+	message, ok := v.(proto.Message)
+	if !ok {
+		return fmt.Errorf("protobuf: %T is not a proto.Message", v)
+	}
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	return proto.Unmarshal(data, message)
+}
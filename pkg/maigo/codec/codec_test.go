@@ -0,0 +1,130 @@
+package codec
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jeanmolossi/maigo/pkg/maigo/mime"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+type item struct {
+	Name  string `json:"name"  yaml:"name"  msgpack:"name"  cbor:"name"`
+	Count int    `json:"count" yaml:"count" msgpack:"count" cbor:"count"`
+}
+
+func TestDefault_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	registry := Default()
+
+	for _, mediaType := range []mime.Type{mime.JSON, mime.YAML, mime.MsgPack, mime.CBOR} {
+		t.Run(mediaType.String(), func(t *testing.T) {
+			t.Parallel()
+
+			codec, ok := registry.Lookup(mediaType)
+			if !ok {
+				t.Fatalf("Lookup(%q) found no codec", mediaType)
+			}
+
+			var buf bytes.Buffer
+
+			in := item{Name: "mai", Count: 2}
+			if err := codec.Encode(&buf, in); err != nil {
+				t.Fatalf("Encode() error = %v", err)
+			}
+
+			var out item
+			if err := codec.Decode(&buf, &out); err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+
+			if out != in {
+				t.Fatalf("Decode() = %+v, want %+v", out, in)
+			}
+		})
+	}
+}
+
+func TestProtobuf_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	if err := (Protobuf{}).Encode(&buf, wrapperspb.String("mai")); err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+
+	out := &wrapperspb.StringValue{}
+	if err := (Protobuf{}).Decode(&buf, out); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+
+	if out.GetValue() != "mai" {
+		t.Fatalf("Decode() = %q, want %q", out.GetValue(), "mai")
+	}
+
+	if err := (Protobuf{}).Encode(&buf, item{}); err == nil {
+		t.Fatalf("Encode() of a non proto.Message succeeded")
+	}
+}
+
+func TestJSON_Options(t *testing.T) {
+	t.Parallel()
+
+	var strict item
+
+	err := JSON{DisallowUnknownFields: true}.Decode(strings.NewReader(`{"name":"mai","extra":1}`), &strict)
+	if err == nil {
+		t.Fatalf("Decode() with an unknown field succeeded")
+	}
+
+	var loose map[string]any
+	if err := (JSON{UseNumber: true}).Decode(strings.NewReader(`{"id":12345678901234567890}`), &loose); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+
+	if got, ok := loose["id"].(json.Number); !ok || got.String() != "12345678901234567890" {
+		t.Fatalf("Decode() id = %#v, want json.Number", loose["id"])
+	}
+}
+
+func TestRegistry_Lookup(t *testing.T) {
+	t.Parallel()
+
+	custom := JSON{UseNumber: true}
+
+	registry := Default()
+	registry.Register("application/vnd.mai+json", custom)
+
+	tests := []struct {
+		mediaType mime.Type
+		want      any
+		found     bool
+	}{
+		{mediaType: "application/json; charset=utf-8", want: JSON{}, found: true},
+		{mediaType: "Application/JSON", want: JSON{}, found: true},
+		{mediaType: "application/problem+json", want: JSON{}, found: true},
+		{mediaType: "application/vnd.mai+json", want: custom, found: true},
+		{mediaType: "application/atom+xml", want: XML{}, found: true},
+		{mediaType: "text/xml", want: XML{}, found: true},
+		{mediaType: "text/csv", found: false},
+		{mediaType: "application/vnd.mai+", found: false},
+	}
+
+	for _, tt := range tests {
+		got, found := registry.Lookup(tt.mediaType)
+		if found != tt.found || (found && !reflect.DeepEqual(got, tt.want)) {
+			t.Errorf("Lookup(%q) = %#v, %v, want %#v, %v", tt.mediaType, got, found, tt.want, tt.found)
+		}
+	}
+
+	registry.Register(mime.JSON, nil)
+
+	if _, found := registry.Lookup(mime.JSON); found {
+		t.Errorf("Lookup(%q) found a removed codec", mime.JSON)
+	}
+}
@@ -0,0 +1,114 @@
+// Package codec provides the codecs used to encode request bodies and decode
+// response bodies, and a registry selecting them by media type.
+package codec
+
+import (
+	"maps"
+	"strings"
+	"sync"
+
+	"github.com/jeanmolossi/maigo/pkg/maigo/contracts"
+	"github.com/jeanmolossi/maigo/pkg/maigo/mime"
+)
+
+var _ contracts.Codecs = (*Registry)(nil)
+
+// Registry is a contracts.Codecs safe for concurrent use.
+type Registry struct {
+	mu     sync.RWMutex
+	codecs map[mime.Type]contracts.Codec
+}
+
+// NewRegistry creates an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{codecs: map[mime.Type]contracts.Codec{}}
+}
+
+// Default creates a registry with the built-in codecs: JSON, XML, YAML,
+// MessagePack, CBOR and protobuf, registered under their common media types.
+func Default() *Registry {
+	r := NewRegistry()
+
+	r.Register(mime.JSON, JSON{})
+	r.Register(mime.XML, XML{})
+	r.Register("text/xml", XML{})
+	r.Register(mime.YAML, YAML{})
+	r.Register("application/x-yaml", YAML{})
+	r.Register("text/yaml", YAML{})
+	r.Register(mime.MsgPack, MsgPack{})
+	r.Register("application/x-msgpack", MsgPack{})
+	r.Register("application/vnd.msgpack", MsgPack{})
+	r.Register(mime.CBOR, CBOR{})
+	r.Register(mime.Protobuf, Protobuf{})
+	r.Register("application/protobuf", Protobuf{})
+
+	return r
+}
+
+// Clone returns a registry with the same codecs, which can be changed
+// without affecting r.
+func (r *Registry) Clone() *Registry {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return &Registry{codecs: maps.Clone(r.codecs)}
+}
+
+// Register implements contracts.Codecs. A nil codec removes the one
+// registered for mediaType.
+func (r *Registry) Register(mediaType mime.Type, codec contracts.Codec) {
+	key := normalize(mediaType)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if codec == nil {
+		delete(r.codecs, key)
+		return
+	}
+
+	r.codecs[key] = codec
+}
+
+// Lookup implements contracts.Codecs.
+func (r *Registry) Lookup(mediaType mime.Type) (contracts.Codec, bool) {
+	key := normalize(mediaType)
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if codec, ok := r.codecs[key]; ok {
+		return codec, true
+	}
+
+	// application/problem+json falls back to application/json
+	if base, ok := suffixType(key); ok {
+		codec, ok := r.codecs[base]
+		return codec, ok
+	}
+
+	return nil, false
+}
+
+// normalize drops the parameters of mediaType and lowercases it.
+func normalize(mediaType mime.Type) mime.Type {
+	value, _, _ := strings.Cut(string(mediaType), ";")
+
+	return mime.Type(strings.ToLower(strings.TrimSpace(value)))
+}
+
+// suffixType maps a media type with a structured syntax suffix, such as
+// application/problem+json, to the media type of the suffix.
+func suffixType(mediaType mime.Type) (mime.Type, bool) {
+	_, subtype, ok := strings.Cut(string(mediaType), "/")
+	if !ok {
+		return "", false
+	}
+
+	i := strings.LastIndex(subtype, "+")
+	if i < 0 || i == len(subtype)-1 {
+		return "", false
+	}
+
+	return mime.Type("application/" + subtype[i+1:]), true
+}
@@ -0,0 +1,42 @@
+package maigo
+
+import (
+	"github.com/jeanmolossi/maigo/pkg/maigo/codec"
+	"github.com/jeanmolossi/maigo/pkg/maigo/contracts"
+	"github.com/jeanmolossi/maigo/pkg/maigo/mime"
+)
+
+var _ contracts.Codecs = layeredCodecs(nil)
+
+// builtinCodecs decodes the responses built outside of a client, such as the
+// ones created by interceptors through NewResponse.
+var builtinCodecs contracts.Codecs = codec.Default()
+
+// layeredCodecs looks codecs up in each registry in turn, so request codecs
+// take precedence over client ones. Codecs are registered in the first one.
+type layeredCodecs []contracts.Codecs
+
+// Register implements contracts.Codecs.
+func (l layeredCodecs) Register(mediaType mime.Type, codec contracts.Codec) {
+	l[0].Register(mediaType, codec)
+}
+
+// Lookup implements contracts.Codecs.
+func (l layeredCodecs) Lookup(mediaType mime.Type) (contracts.Codec, bool) {
+	for _, codecs := range l {
+		if codecs == nil {
+			continue
+		}
+
+		if codec, ok := codecs.Lookup(mediaType); ok {
+			return codec, true
+		}
+	}
+
+	return nil, false
+}
+
+// codecs returns the codecs of the request layered over the client ones.
+func (r *RequestBuilder) codecs() contracts.Codecs {
+	return layeredCodecs{r.request.config.Codecs(), r.request.client.Codecs()}
+}
@@ -0,0 +1,156 @@
+package maigo
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jeanmolossi/maigo/pkg/maigo/codec"
+	"github.com/jeanmolossi/maigo/pkg/maigo/header"
+	"github.com/jeanmolossi/maigo/pkg/maigo/mime"
+)
+
+type codecUser struct {
+	Name string `json:"name" yaml:"name" msgpack:"name"`
+	Age  int    `json:"age"  yaml:"age"  msgpack:"age"`
+}
+
+func TestCodec_AsAndDecode(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer(t, echo)
+
+	for _, mediaType := range []mime.Type{mime.JSON, mime.YAML, mime.MsgPack} {
+		t.Run(mediaType.String(), func(t *testing.T) {
+			t.Parallel()
+
+			in := codecUser{Name: "mai", Age: 3}
+
+			resp, err := DefaultClient(server.URL).
+				POST("/").
+				Body().As(mediaType, in).
+				Send()
+			if err != nil {
+				t.Fatalf("Send() error = %v", err)
+			}
+
+			if got := resp.Request().Headers().Get(header.Accept.String()); got != mediaType.String() {
+				t.Fatalf("Accept = %q, want %q", got, mediaType)
+			}
+
+			var out codecUser
+			if err := resp.Body().Decode(&out); err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+
+			if out != in {
+				t.Fatalf("Decode() = %+v, want %+v", out, in)
+			}
+		})
+	}
+}
+
+func TestCodec_ClientOptionsApplyToResponses(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set(header.ContentType.String(), "application/problem+json")
+		_, _ = io.WriteString(w, `{"name":"mai","age":3,"extra":true}`)
+	}))
+	t.Cleanup(server.Close)
+
+	client := NewClient(server.URL).
+		Codec().Register(mime.JSON, codec.JSON{DisallowUnknownFields: true}).
+		Build()
+
+	resp, err := client.GET("/").Send()
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	var out codecUser
+	if err := resp.Body().Decode(&out); !errors.Is(err, ErrToDecodeBody) {
+		t.Fatalf("Decode() error = %v, want %v", err, ErrToDecodeBody)
+	}
+
+	resp, err = client.GET("/").Send()
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	if _, err := AsJSON[codecUser](resp.Body().(*ResponseBody)); !errors.Is(err, ErrToDecodeBody) {
+		t.Fatalf("AsJSON() error = %v, want %v", err, ErrToDecodeBody)
+	}
+}
+
+// upperCodec encodes strings in upper case, standing in for a custom format.
+type upperCodec struct{}
+
+func (upperCodec) Encode(w io.Writer, v any) error {
+	_, err := io.WriteString(w, strings.ToUpper(v.(string)))
+	return err
+}
+
+func (upperCodec) Decode(r io.Reader, v any) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	*v.(*string) = strings.ToLower(string(data))
+
+	return nil
+}
+
+func TestCodec_RequestCodecTakesPrecedence(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer(t, echo)
+
+	resp, err := DefaultClient(server.URL).
+		POST("/").
+		Codec().Register(mime.Text, upperCodec{}).
+		Body().As(mime.Text, "hello").
+		Send()
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	body, err := resp.Body().AsString()
+	if err != nil || body != "HELLO" {
+		t.Fatalf("AsString() = %q, %v, want %q", body, err, "HELLO")
+	}
+}
+
+func TestCodec_Errors(t *testing.T) {
+	t.Parallel()
+
+	_, err := DefaultClient("https://example.com").
+		POST("/").
+		Body().As(mime.CSV, []string{"a"}).
+		Unwrap()
+	if !errors.Is(err, ErrUnsupportedMediaType) {
+		t.Fatalf("Unwrap() error = %v, want %v", err, ErrUnsupportedMediaType)
+	}
+
+	_, err = DefaultClient("https://example.com").
+		POST("/").
+		Codec().Register(mime.CSV, nil).
+		Unwrap()
+	if !errors.Is(err, ErrInvalidCodec) {
+		t.Fatalf("Unwrap() error = %v, want %v", err, ErrInvalidCodec)
+	}
+
+	resp := NewResponse(&http.Response{
+		Header: http.Header{header.ContentType.String(): {"text/csv"}},
+		Body:   io.NopCloser(strings.NewReader("a,b")),
+	})
+
+	var out []string
+	if err := resp.Body().Decode(&out); !errors.Is(err, ErrUnsupportedMediaType) {
+		t.Fatalf("Decode() error = %v, want %v", err, ErrUnsupportedMediaType)
+	}
+}
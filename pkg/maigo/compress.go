@@ -0,0 +1,94 @@
+package maigo
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/jeanmolossi/maigo/pkg/httpx/compression"
+	"github.com/jeanmolossi/maigo/pkg/maigo/header"
+)
+
+var (
+	_ bodySource = (*bytesSource)(nil)
+	_ bodySource = (*compressedSource)(nil)
+)
+
+// compressBody returns the body compressed with the encoding set through
+// Body().Compress, or nil when it is sent as is: without an encoding, below
+// the size threshold, or when the headers already define a Content-Encoding.
+// Streamed bodies of unknown size are always compressed.
+func (r *RequestBuilder) compressBody() (bodySource, error) {
+	config := r.request.config
+
+	if config.compression == "" || r.hasContentEncoding() {
+		return nil, nil
+	}
+
+	if source := config.bodySource; source != nil {
+		if size := source.size(); size >= 0 && size < config.compressionMinSize {
+			return nil, nil
+		}
+
+		return &compressedSource{source: source, encoding: config.compression}, nil
+	}
+
+	data, err := io.ReadAll(config.body.Unwrap())
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) == 0 || int64(len(data)) < config.compressionMinSize {
+		return nil, nil
+	}
+
+	compressed, err := compression.Compress(data, config.compression)
+	if err != nil {
+		return nil, err
+	}
+
+	return &bytesSource{data: compressed}, nil
+}
+
+func (r *RequestBuilder) hasContentEncoding() bool {
+	key := header.ContentEncoding.String()
+
+	return r.request.client.Header().Unwrap().Get(key) != "" ||
+		r.request.config.Header().Unwrap().Get(key) != ""
+}
+
+// bytesSource is a body held in memory.
+type bytesSource struct {
+	data []byte
+}
+
+// open implements bodySource.
+func (b *bytesSource) open() (io.ReadCloser, error) {
+	return io.NopCloser(bytes.NewReader(b.data)), nil
+}
+
+// size implements bodySource.
+func (b *bytesSource) size() int64 {
+	return int64(len(b.data))
+}
+
+// compressedSource streams source compressed with encoding.
+type compressedSource struct {
+	source   bodySource
+	encoding compression.Encoding
+}
+
+// open implements bodySource.
+func (c *compressedSource) open() (io.ReadCloser, error) {
+	stream, err := c.source.open()
+	if err != nil {
+		return nil, err
+	}
+
+	return compression.CompressReader(stream, c.encoding)
+}
+
+// size implements bodySource. The compressed size is only known once the
+// body is read.
+func (c *compressedSource) size() int64 {
+	return -1
+}
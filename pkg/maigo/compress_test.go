@@ -0,0 +1,202 @@
+package maigo
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jeanmolossi/maigo/pkg/httpx/compression"
+	"github.com/klauspost/compress/zstd"
+)
+
+type compressedRequest struct {
+	encoding string
+	body     string
+}
+
+// decoded returns the decoded body of every request server received.
+func decoded(t *testing.T, server *testServer) []compressedRequest {
+	t.Helper()
+
+	var seen []compressedRequest
+
+	for _, request := range server.Requests() {
+		var (
+			reader io.Reader = bytes.NewReader(request.body)
+			err    error
+		)
+
+		encoding := request.header.Get("Content-Encoding")
+
+		switch encoding {
+		case "gzip":
+			reader, err = gzip.NewReader(reader)
+		case "zstd":
+			var zr *zstd.Decoder
+
+			zr, err = zstd.NewReader(reader)
+			if err == nil {
+				defer zr.Close()
+			}
+
+			reader = zr
+		}
+
+		if err != nil {
+			t.Fatalf("decode %s body: %v", encoding, err)
+		}
+
+		body, err := io.ReadAll(reader)
+		if err != nil {
+			t.Fatalf("decode %s body: %v", encoding, err)
+		}
+
+		seen = append(seen, compressedRequest{encoding: encoding, body: string(body)})
+	}
+
+	return seen
+}
+
+func TestBodyCompress_ReplaysOnRetry(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer(t, inTurn(respond(http.StatusServiceUnavailable, ""), respond(http.StatusOK, "")))
+	payload := strings.Repeat(`{"id":1}`, 200)
+
+	resp, err := DefaultClient(server.URL).
+		POST("/").
+		Body().AsString(payload).
+		Body().Compress(compression.Gzip, 1024).
+		Retry().SetConstantBackoff(time.Millisecond, 2).
+		Send()
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	if !resp.Status().IsOK() {
+		t.Fatalf("status = %d, want 200", resp.Status().Code())
+	}
+
+	want := compressedRequest{encoding: "gzip", body: payload}
+	if got := decoded(t, server); len(got) != 2 || got[0] != want || got[1] != want {
+		t.Fatalf("server saw %d requests, want 2 gzip requests with the payload", len(got))
+	}
+}
+
+func TestBodyCompress_BelowThreshold(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer(t, respond(http.StatusOK, ""))
+
+	_, err := DefaultClient(server.URL).
+		POST("/").
+		Body().Compress(compression.Gzip, 1024).
+		Body().AsString("small").
+		Send()
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	if got := decoded(t, server); len(got) != 1 || got[0] != (compressedRequest{body: "small"}) {
+		t.Fatalf("server saw %+v, want an uncompressed body", got)
+	}
+}
+
+func TestBodyCompress_Multipart(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer(t, respond(http.StatusOK, ""))
+
+	_, err := DefaultClient(server.URL).
+		POST("/").
+		Body().AsMultipart().Field("name", "mai").Done().
+		Body().Compress(compression.Zstd, 0).
+		Send()
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	got := decoded(t, server)
+	if len(got) != 1 || got[0].encoding != "zstd" || !strings.Contains(got[0].body, "mai") {
+		t.Fatalf("server saw %+v, want a zstd multipart body", got)
+	}
+}
+
+func TestBodyCompress_UnsupportedEncoding(t *testing.T) {
+	t.Parallel()
+
+	_, err := DefaultClient("https://example.com").
+		POST("/").
+		Body().Compress("lzma", 0).
+		Unwrap()
+	if !errors.Is(err, compression.ErrUnsupportedEncoding) {
+		t.Fatalf("Unwrap() error = %v, want %v", err, compression.ErrUnsupportedEncoding)
+	}
+}
+
+func TestClientDecompression(t *testing.T) {
+	t.Parallel()
+
+	payload := `{"name":"mai"}`
+
+	encoded, err := compression.Compress([]byte(payload), compression.Brotli)
+	if err != nil {
+		t.Fatalf("Compress() error = %v", err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.Contains(r.Header.Get("Accept-Encoding"), "br") {
+			w.WriteHeader(http.StatusNotAcceptable)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Encoding", "br")
+		_, _ = w.Write(encoded)
+	}))
+	t.Cleanup(server.Close)
+
+	resp, err := NewClient(server.URL).
+		Config().SetDecompression().
+		Build().
+		GET("/").
+		Send()
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	if got := resp.Body().ContentEncoding(); got != "br" {
+		t.Fatalf("ContentEncoding() = %q, want br", got)
+	}
+
+	var body struct {
+		Name string `json:"name"`
+	}
+
+	if err := resp.Body().AsJSON(&body); err != nil {
+		t.Fatalf("AsJSON() error = %v", err)
+	}
+
+	if body.Name != "mai" {
+		t.Fatalf("name = %q, want mai", body.Name)
+	}
+}
+
+func TestClientDecompression_UnsupportedEncoding(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewClient("https://example.com").
+		Config().SetDecompression("lzma").
+		Build().
+		GET("/").
+		Unwrap()
+	if !errors.Is(err, compression.ErrUnsupportedEncoding) {
+		t.Fatalf("Unwrap() error = %v, want %v", err, compression.ErrUnsupportedEncoding)
+	}
+}
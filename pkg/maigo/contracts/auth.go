@@ -0,0 +1,68 @@
+package contracts
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// Authenticator applies credentials to an outgoing request. Implementations
+// are attached to a client or to a single request through BuilderAuth and are
+// invoked once the *http.Request has been created, after headers and query
+// parameters were copied.
+//
+// Example:
+//
+//	type signer struct{ key string }
+//
+//	func (s signer) Authenticate(r *http.Request) error {
+//	    r.Header.Set("X-Signature", sign(s.key, r))
+//	    return nil
+//	}
+type Authenticator interface {
+	// Authenticate writes the credentials into r.
+	Authenticate(r *http.Request) error
+}
+
+// ConfigAuth allows replacing or retrieving the authenticator applied to
+// requests.
+type ConfigAuth interface {
+	// SetAuthenticator replaces the current authenticator. Passing nil
+	// disables authentication.
+	SetAuthenticator(auth Authenticator)
+	// Authenticator retrieves the current authenticator or nil when none is
+	// configured.
+	Authenticator() Authenticator
+}
+
+// RefreshableAuthenticator is an Authenticator able to renew its credentials
+// when the server rejects a request with 401 Unauthorized.
+type RefreshableAuthenticator interface {
+	Authenticator
+	// Refresh renews the credentials rejected for r and reports whether r
+	// should be replayed with the new credentials.
+	Refresh(r *http.Request) (bool, error)
+}
+
+// Token is an access token issued by an authorization server, as described
+// by RFC 6749 §5.1.
+type Token struct {
+	// AccessToken is the token sent with requests.
+	AccessToken string
+	// TokenType is the type of the token, usually "Bearer". An empty value is
+	// treated as "Bearer".
+	TokenType string
+	// RefreshToken is used to obtain a new access token when supported by
+	// the grant.
+	RefreshToken string
+	// Expiry is when the access token expires. A zero value means the token
+	// does not expire.
+	Expiry time.Time
+}
+
+// TokenSource supplies access tokens, typically by requesting them from an
+// OAuth2 token endpoint. Implementations must be safe for concurrent use.
+type TokenSource interface {
+	// Token returns a token or an error if it cannot be obtained.
+	Token(ctx context.Context) (*Token, error)
+}
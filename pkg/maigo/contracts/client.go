@@ -8,6 +8,8 @@ import (
 	"net/url"
 	"time"
 
+	"github.com/jeanmolossi/maigo/pkg/httpx"
+	"github.com/jeanmolossi/maigo/pkg/httpx/compression"
 	"github.com/jeanmolossi/maigo/pkg/maigo/header"
 	"github.com/jeanmolossi/maigo/pkg/maigo/mime"
 )
@@ -46,14 +48,39 @@ type ClientBuilder interface {
 	Header() BuilderHeader[ClientBuilder]
 	// Cookie returns a builder to configure default cookies.
 	Cookie() BuilderCookie[ClientBuilder]
+	// Auth returns a builder to configure default credentials.
+	Auth() BuilderAuth[ClientBuilder]
+	// Intercept returns a builder to register interceptors running around
+	// every request sent by the client.
+	Intercept() BuilderInterceptor[ClientBuilder]
+	// Codec returns a builder to register the codecs used by Body().As and
+	// the response Body().Decode.
+	Codec() BuilderCodec[ClientBuilder]
+	// Use layers middlewares over the client's transport. The request goes
+	// down through them in the order they were added. Transport settings
+	// made through Config apply to the base transport beneath them,
+	// regardless of call order.
+	Use(middlewares ...httpx.ChainedRoundTripper) ClientBuilder
+	// UseNamed is like Use for a single middleware reported as name by the
+	// client's httpx.Chain.
+	UseNamed(name string, middleware httpx.ChainedRoundTripper) ClientBuilder
+	// ExpectSuccess makes every request of the client report non-2xx
+	// responses as errors, as RequestBuilder.ExpectSuccess does.
+	ExpectSuccess() ClientBuilder
 	// Build finalizes the configuration and produces a ClientHTTPMethods.
 	Build() ClientHTTPMethods
 }
 
 // ClientConfig exposes the configurable parts of a client, such as the
-// underlying HTTP client, default headers, cookies, validations and base URL.
+// underlying HTTP client, default headers, cookies, credentials, validations
+// and base URL.
 type ClientConfig interface {
 	ConfigHTTPClient
+	ConfigAuth
+	ConfigInterceptors
+	ConfigMediaType
+	ConfigCodecs
+	ConfigExpectSuccess
 	// Header exposes the client's default headers.
 	Header() Header
 	// Cookies exposes the client's cookie jar.
@@ -63,6 +90,16 @@ type ClientConfig interface {
 	ConfigBaseURL
 }
 
+// ConfigExpectSuccess allows enabling or retrieving whether non-2xx responses
+// are reported as errors.
+type ConfigExpectSuccess interface {
+	// ExpectsSuccess reports whether non-2xx responses are errors.
+	ExpectsSuccess() bool
+	// SetExpectSuccess enables or disables reporting non-2xx responses as
+	// errors.
+	SetExpectSuccess(expect bool)
+}
+
 // ConfigHTTPClient allows replacing or retrieving the underlying HTTP client.
 type ConfigHTTPClient interface {
 	// SetHttpClient replaces the underlying HTTP client implementation.
@@ -120,17 +157,65 @@ type ClientHTTPMethods interface {
 	OPTIONS(path string) RequestBuilder
 	// TRACE prepares a TRACE request for the given path.
 	TRACE(path string) RequestBuilder
+	// With returns a builder for a child client. The child shares the
+	// transport, and so the connection pool, of this client and starts
+	// with a copy of its headers, cookies, credentials, interceptors,
+	// codecs and middlewares. Changes made through the builder apply to the
+	// child only.
+	//
+	// Example:
+	//
+	//	admin := client.With().
+	//	        Header().Set(header.Authorization, "Bearer admin").
+	//	        Config().SetTimeout(time.Minute).
+	//	        Build()
+	With() ClientBuilder
 }
 
 // ConfigBaseURL exposes the base URL used to resolve request paths.
 type ConfigBaseURL interface {
 	// BaseURL returns the base URL used to resolve request paths.
 	BaseURL() *url.URL
+	// AcquireBaseURL returns the base URL of a request about to be sent and
+	// a function to call once the request is complete, its response body
+	// closed or its sending failed. The function may be called more than
+	// once.
+	AcquireBaseURL() (*url.URL, func())
+}
+
+// Backend is one of the base URLs of a load-balanced client.
+type Backend interface {
+	// URL returns the base URL.
+	URL() *url.URL
+	// Weight returns the share of requests the backend should receive,
+	// relative to the others. It defaults to 1.
+	Weight() int
+	// InFlight returns how many requests sent to the backend are not
+	// complete yet.
+	InFlight() int64
+}
+
+// BalanceStrategy chooses which backend of a load-balanced client serves
+// each request. Implementations must be safe for concurrent use.
+//
+// Example:
+//
+//	client := maigo.NewClientLoadBalancer(urls,
+//	        maigo.WithStrategy(maigo.LeastInFlight()),
+//	).Build()
+type BalanceStrategy interface {
+	// Pick returns the index of the backend serving the next request. It is
+	// called with at least two backends.
+	Pick(backends []Backend) int
 }
 
 // BuilderHeader configures HTTP headers for the parent builder. Each method
 // returns the parent type so calls can be chained.
 //
+// Request headers are merged with the client ones: values added or set on a
+// request replace the client values, appended values are sent after them
+// and removed headers are not sent at all.
+//
 // Example:
 //
 //	builder.Header().
@@ -151,15 +236,39 @@ type BuilderHeader[T any] interface {
 	AddContentType(value mime.Type) T
 	// AddUserAgent adds a User-Agent header.
 	AddUserAgent(value string) T
+	// Append adds a header value. On a request, it is sent after the client
+	// values instead of replacing them.
+	Append(key header.Type, value string) T
+	// Remove deletes the header. On a request, it also drops the client
+	// default for it.
+	Remove(key header.Type) T
 }
 
 // BuilderCookie adds cookies to the parent builder.
 type BuilderCookie[T any] interface {
 	// Add includes a cookie to be sent with requests.
 	Add(cookie *http.Cookie) T
+	// Jar stores the cookies set by responses in jar and sends them back
+	// with later requests to the same site, redirects included. The jar
+	// package provides an in-memory jar aware of the public suffix list and
+	// one persisted to a file. A nil jar stops storing cookies.
+	Jar(jar http.CookieJar) T
 }
 
-// BuilderAuth configures authentication headers like Bearer or Basic auth.
+// BuilderAuth configures the credentials sent with requests, like Bearer or
+// Basic auth and API keys. Each call replaces the credentials configured
+// previously on the same builder. Credentials configured on a request take
+// precedence over the ones configured on its client.
+//
+// Example:
+//
+//	client := maigo.NewClient("https://api.example.com").
+//	        Auth().BearerToken("client-token").
+//	        Build()
+//
+//	resp, err := client.GET("/admin").
+//	        Auth().BasicAuth("admin", "secret").
+//	        Send()
 type BuilderAuth[T any] interface {
 	// Set writes the Authorization header as provided.
 	Set(value string) T
@@ -167,6 +276,16 @@ type BuilderAuth[T any] interface {
 	BearerToken(token string) T
 	// BasicAuth sets the Authorization header using basic auth credentials.
 	BasicAuth(user, pass string) T
+	// APIKeyHeader sends the API key in the given header.
+	APIKeyHeader(key header.Type, value string) T
+	// APIKeyQuery sends the API key in the given query parameter.
+	APIKeyQuery(param, value string) T
+	// TokenSource sends tokens obtained from source as the Authorization
+	// header. Tokens are cached until they expire and renewed once when the
+	// server answers 401 Unauthorized, replaying the rejected request.
+	TokenSource(source TokenSource) T
+	// Custom uses the provided Authenticator.
+	Custom(auth Authenticator) T
 }
 
 // BuilderHTTPClientConfig tunes the behaviour of the underlying HTTP client,
@@ -184,23 +303,76 @@ type BuilderHTTPClientConfig[T any] interface {
 	SetFollowRedirects(follow bool) T
 	// SetProxy configures an HTTP proxy via URL.
 	SetProxy(proxyURL string) T
+	// SetMediaTypeMode controls the Content-Type and Accept headers derived
+	// from request bodies. Defaults to MediaTypeAuto.
+	SetMediaTypeMode(mode MediaTypeMode) T
+	// SetDecompression adds a middleware advertising encodings, or gzip,
+	// deflate, br and zstd when none is given, through Accept-Encoding and
+	// decoding the response bodies sent with any of them, even when the
+	// Accept-Encoding is set by hand. Middlewares added after it see the
+	// compressed responses.
+	SetDecompression(encodings ...compression.Encoding) T
 }
 
 // BuilderRequestContext sets the context used when sending a request.
 type BuilderRequestContext[T any] interface {
 	// Set defines the context to use when the request is sent.
 	Set(ctx context.Context) T
+	// SetTimeout bounds the whole call, retries and body reading included,
+	// to d. It composes with the context deadline, the earliest one wins.
+	SetTimeout(d time.Duration) T
+	// SetDeadline bounds the whole call to the absolute time t. It composes
+	// with the context deadline and SetTimeout, the earliest one wins.
+	SetDeadline(t time.Time) T
+	// SetAttemptTimeout bounds each attempt, including each retry, to d.
+	SetAttemptTimeout(d time.Duration) T
 }
 
 // BuilderRequestBody serializes values into the request body.
-// Supported formats include raw readers, strings, JSON and XML.
+// Supported formats include raw readers, strings, URL-encoded forms,
+// multipart forms and any media type with a registered codec, such as JSON,
+// XML, YAML, MessagePack, CBOR or protobuf. Except for raw readers, the body
+// media type is sent as Content-Type, and as Accept for codec encoded bodies,
+// according to the client MediaTypeMode.
 type BuilderRequestBody[T any] interface {
 	// AsReader uses the raw reader as the request body.
 	AsReader(body io.Reader) T
-	// AsString writes the provided string as the request body.
+	// AsString writes the provided string as a text/plain request body.
 	AsString(body string) T
-	// AsJSON serializes obj as JSON into the request body.
+	// AsJSON serializes obj with the application/json codec into the
+	// request body, setting the Content-Type and Accept to application/json.
 	AsJSON(obj any) T
-	// AsXML serializes obj as XML into the request body.
+	// AsXML serializes obj with the application/xml codec into the request
+	// body, setting the Content-Type and Accept to application/xml.
 	AsXML(obj any) T
+	// As serializes obj with the codec registered for mediaType, looked up
+	// on the request first and on the client next, setting the Content-Type
+	// and Accept to mediaType. Codecs registered on the request only apply
+	// to bodies set after them.
+	As(mediaType mime.Type, obj any) T
+	// AsForm encodes values as an application/x-www-form-urlencoded body
+	// and sets the Content-Type unless the request already defines one.
+	AsForm(values url.Values) T
+	// AsFormStruct encodes obj, a struct or a map with string keys, as an
+	// application/x-www-form-urlencoded body driven by `form:"name,omitempty"`
+	// tags. Nested structs and maps use bracket keys such as "address[city]",
+	// slices of scalars repeat the key and slices of structs are indexed, as in
+	// "items[0][name]". It sets the Content-Type like AsForm.
+	AsFormStruct(obj any) T
+	// AsMultipart starts a streamed multipart/form-data body and sets its
+	// Content-Type, boundary included, unless the request defines one.
+	AsMultipart() BuilderRequestMultipart[T]
+	// Compress compresses bodies of at least minSize bytes with encoding and
+	// sets the Content-Encoding, unless the request already defines one.
+	// Streamed bodies of unknown size are always compressed. Compressed
+	// bodies are produced again when the request is retried.
+	Compress(encoding compression.Encoding, minSize int64) T
+	// OnUploadProgress reports the progress of sending the body to fn, at
+	// most once per interval and always on completion. A zero interval
+	// reports every read. A retried body reports again from zero.
+	OnUploadProgress(fn ProgressFunc, interval time.Duration) T
+	// OnDownloadProgress reports the progress of reading the response body
+	// to fn, as OnUploadProgress does. Being set on the request, it also
+	// covers requests sent by someone else, such as async.Dispatch.
+	OnDownloadProgress(fn ProgressFunc, interval time.Duration) T
 }
@@ -0,0 +1,47 @@
+package contracts
+
+import (
+	"io"
+
+	"github.com/jeanmolossi/maigo/pkg/maigo/mime"
+)
+
+// Codec encodes and decodes bodies of a given media type, such as JSON or
+// MessagePack. Implementations must be safe for concurrent use.
+type Codec interface {
+	// Encode writes v to w.
+	Encode(w io.Writer, v any) error
+	// Decode reads r into v.
+	Decode(r io.Reader, v any) error
+}
+
+// Codecs is a registry of codecs keyed by media type. Media type parameters
+// are ignored, and structured syntax suffixes fall back to their base type,
+// so "application/problem+json; charset=utf-8" uses the application/json
+// codec unless a more specific one is registered.
+type Codecs interface {
+	// Register associates codec with mediaType, replacing any previous one.
+	Register(mediaType mime.Type, codec Codec)
+	// Lookup returns the codec associated with mediaType.
+	Lookup(mediaType mime.Type) (Codec, bool)
+}
+
+// ConfigCodecs exposes the codecs used to encode request bodies and decode
+// response bodies.
+type ConfigCodecs interface {
+	// Codecs returns the codec registry.
+	Codecs() Codecs
+}
+
+// BuilderCodec registers codecs on the parent builder. Codecs registered on
+// a request take precedence over the ones registered on its client.
+//
+// Example:
+//
+//	client := maigo.NewClient("https://api.example.com").
+//	        Codec().Register(mime.JSON, codec.JSON{DisallowUnknownFields: true}).
+//	        Build()
+type BuilderCodec[T any] interface {
+	// Register associates codec with mediaType.
+	Register(mediaType mime.Type, codec Codec) T
+}
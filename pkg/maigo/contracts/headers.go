@@ -22,3 +22,13 @@ type Header interface {
 	// Set replaces any existing values of key with value.
 	Set(key header.Type, value string)
 }
+
+// HeaderEditor is implemented by a Header that can also append and remove
+// values, as used by BuilderHeader.Append and BuilderHeader.Remove.
+type HeaderEditor interface {
+	// Append is like Add, but request values appended this way follow the
+	// client values instead of replacing them.
+	Append(key header.Type, value string)
+	// Del removes all values of key.
+	Del(key header.Type)
+}
@@ -0,0 +1,55 @@
+package contracts
+
+// RequestInterceptor runs before a request is built and sent, receiving the
+// fluent builder so it can change headers, query parameters, credentials or
+// any other setting. Returning a non-nil Response short-circuits the call:
+// nothing is sent and the response is handed to the response interceptors.
+// Returning an error aborts the call with that error, and returning neither
+// lets the call proceed.
+//
+// Example:
+//
+//	func tenant(req contracts.RequestBuilder) (contracts.Response, error) {
+//	    req.Header().Set("X-Tenant", "mai")
+//	    return nil, nil
+//	}
+type RequestInterceptor func(req RequestBuilder) (Response, error)
+
+// ResponseInterceptor runs after a response is received. It may return the
+// same response, replace it, or turn it into an error. Returning a nil
+// Response without an error keeps the current one.
+//
+// Example:
+//
+//	func notFound(resp contracts.Response) (contracts.Response, error) {
+//	    if resp.Status().IsNotFound() {
+//	        return nil, ErrUserNotFound
+//	    }
+//	    return resp, nil
+//	}
+type ResponseInterceptor func(resp Response) (Response, error)
+
+// ConfigInterceptors allows registering or retrieving interceptors.
+type ConfigInterceptors interface {
+	// AddRequestInterceptor appends a request interceptor.
+	AddRequestInterceptor(interceptor RequestInterceptor)
+	// AddResponseInterceptor appends a response interceptor.
+	AddResponseInterceptor(interceptor ResponseInterceptor)
+	// RequestInterceptors returns the request interceptors in the order they
+	// were registered.
+	RequestInterceptors() []RequestInterceptor
+	// ResponseInterceptors returns the response interceptors in the order
+	// they were registered.
+	ResponseInterceptors() []ResponseInterceptor
+}
+
+// BuilderInterceptor registers interceptors running around Send. Request
+// interceptors run in registration order, client ones before request ones;
+// response interceptors run in the reverse order, request ones before client
+// ones.
+type BuilderInterceptor[T any] interface {
+	// OnRequest registers an interceptor invoked before the request is built.
+	OnRequest(interceptor RequestInterceptor) T
+	// OnResponse registers an interceptor invoked once a response is available.
+	OnResponse(interceptor ResponseInterceptor) T
+}
@@ -0,0 +1,27 @@
+package contracts
+
+// MediaTypeMode controls the Content-Type and Accept headers the request body
+// builders derive from the body they produce, such as application/json for
+// AsJSON.
+type MediaTypeMode uint8
+
+const (
+	// MediaTypeAuto sets the headers unless the client or request headers
+	// already define them. It is the default.
+	MediaTypeAuto MediaTypeMode = iota
+	// MediaTypeStrict sets the headers like MediaTypeAuto and fails request
+	// validation when a Content-Type header contradicts the body.
+	MediaTypeStrict
+	// MediaTypeOff never sets the headers. Multipart bodies still get their
+	// Content-Type, since the boundary cannot be known otherwise.
+	MediaTypeOff
+)
+
+// ConfigMediaType allows replacing or retrieving the MediaTypeMode applied to
+// requests.
+type ConfigMediaType interface {
+	// MediaTypeMode returns the current mode.
+	MediaTypeMode() MediaTypeMode
+	// SetMediaTypeMode replaces the current mode.
+	SetMediaTypeMode(mode MediaTypeMode)
+}
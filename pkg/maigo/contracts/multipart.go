@@ -0,0 +1,37 @@
+package contracts
+
+import (
+	"io"
+	"net/textproto"
+)
+
+// BuilderRequestMultipart streams a multipart/form-data body made of fields,
+// files and custom parts. Parts are written through a pipe as the request is
+// sent, so memory use does not depend on their size, and the Content-Length
+// is set whenever every part size is known. File paths are reopened and
+// seekable readers rewound when the request is retried; other readers can
+// only be sent once.
+//
+// Example:
+//
+//	client.POST("/uploads").
+//	        Body().AsMultipart().
+//	        Field("title", "report").
+//	        FilePath("file", "./report.pdf").
+//	        Done().
+//	        Send()
+type BuilderRequestMultipart[T any] interface {
+	// Field adds a form field.
+	Field(name, value string) BuilderRequestMultipart[T]
+	// File adds a file field named field whose contents are read from
+	// content and sent as filename.
+	File(field, filename string, content io.Reader) BuilderRequestMultipart[T]
+	// FilePath adds a file field named field with the contents of the file at
+	// path. The file is opened only when the request is sent.
+	FilePath(field, path string) BuilderRequestMultipart[T]
+	// Part adds a part with custom headers, which should include its
+	// Content-Disposition.
+	Part(header textproto.MIMEHeader, content io.Reader) BuilderRequestMultipart[T]
+	// Done returns to the request builder.
+	Done() T
+}
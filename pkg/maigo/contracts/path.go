@@ -0,0 +1,17 @@
+package contracts
+
+// BuilderRequestPath fills the placeholders of a templated request path, such
+// as "/users/{id}/orders/{orderId}". Values are escaped as a single path
+// segment, and every placeholder must be filled before the request is built.
+//
+// Example:
+//
+//	client.GET("/users/{id}/orders/{orderId}").
+//	        Path().Param("id", "42").
+//	        Path().Param("orderId", "a/b")
+type BuilderRequestPath[T any] interface {
+	// Param sets the value of the name placeholder.
+	Param(name, value string) T
+	// Params sets the values of multiple placeholders.
+	Params(params Params) T
+}
@@ -0,0 +1,36 @@
+package contracts
+
+import "time"
+
+// Progress is a snapshot of a body transfer.
+type Progress struct {
+	// Transferred is the number of bytes read so far.
+	Transferred int64
+	// Total is the size of the body, or -1 when it is unknown.
+	Total int64
+	// Elapsed is the time since the transfer started.
+	Elapsed time.Duration
+	// Rate is the average transfer rate in bytes per second.
+	Rate float64
+	// Done is set on the last report, once the body was fully read.
+	Done bool
+}
+
+// Percent reports the share of the body transferred, from 0 to 100, or -1
+// when the total is unknown.
+func (p Progress) Percent() float64 {
+	if p.Total < 0 {
+		return -1
+	}
+
+	if p.Total == 0 {
+		return 100
+	}
+
+	return float64(p.Transferred) * 100 / float64(p.Total)
+}
+
+// ProgressFunc receives the progress of a transfer. It is called from the
+// goroutine reading the body, which for uploads is owned by the transport,
+// so it must be safe for concurrent use when shared between requests.
+type ProgressFunc func(progress Progress)
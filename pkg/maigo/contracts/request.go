@@ -21,12 +21,44 @@ type RequestBuilder interface {
 	Retry() BuilderRequestRetry[RequestBuilder]
 	// Context returns a builder for setting the request context.
 	Context() BuilderRequestContext[RequestBuilder]
+	// Path returns a builder for filling the path template placeholders.
+	Path() BuilderRequestPath[RequestBuilder]
 	// Query returns a builder for setting query parameters.
 	Query() BuilderRequestQuery[RequestBuilder]
+	// Auth returns a builder for setting the request credentials, overriding
+	// the ones configured on the client.
+	Auth() BuilderAuth[RequestBuilder]
+	// Intercept returns a builder to register interceptors running around
+	// Send, after the client ones on the way out and before them on the way
+	// back.
+	Intercept() BuilderInterceptor[RequestBuilder]
+	// Codec returns a builder to register codecs for this request only,
+	// taking precedence over the client ones.
+	Codec() BuilderCodec[RequestBuilder]
 
-	// Send executes the HTTP request.
+	// ExpectSuccess makes Send report a non-2xx response, once retries and
+	// interceptors are done with it, as a *maigo.HTTPError[[]byte] holding
+	// the start of the body. The response body is closed.
+	ExpectSuccess() RequestBuilder
+
+	// Clone returns an independent copy of the builder, with its own
+	// headers, query, cookies, body, retry settings and context, so a
+	// configured request can serve as a template:
+	//
+	//	users := client.GET("/users").Header().Set(header.Accept, "application/json")
+	//	page2 := users.Clone().Query().AddParam("page", "2")
+	Clone() RequestBuilder
+
+	// Send executes the HTTP request. Each call works on a copy of the
+	// builder taken when it starts, so the same builder can be sent many
+	// times, also concurrently, as long as it is not changed meanwhile.
 	Send() (Response, error)
 
+	// SendAnyStatus is like Send but returns the response whatever its
+	// status, ignoring ExpectSuccess, for callers handling the status
+	// themselves.
+	SendAnyStatus() (Response, error)
+
 	// Unwrap finalizes the builder and returns a fully configured *http.Request
 	// without executing it.
 	Unwrap() (*http.Request, error)
@@ -3,6 +3,9 @@ package contracts
 import (
 	"io"
 	"net/http"
+	"time"
+
+	"github.com/jeanmolossi/maigo/pkg/maigo/digest"
 )
 
 // Response represents an HTTP response and provides fluent helpers for
@@ -42,12 +45,41 @@ type ResponseFluentBody interface {
 	AsJSON(v any) error
 	// AsXML decodes the body as XML into v.
 	AsXML(v any) error
+	// Decode decodes the body into v with the codec registered for the
+	// response Content-Type.
+	Decode(v any) error
+	// OnProgress reports the progress of reading the body to fn, at most
+	// once per interval and always on completion. It must be called before
+	// the body is read.
+	OnProgress(fn ProgressFunc, interval time.Duration) ResponseFluentBody
+	// ToWriter streams the body to w without buffering it and returns the
+	// number of bytes written. The body is verified against checksums and
+	// the digests announced in the Content-Digest, Repr-Digest and
+	// Content-MD5 headers; on a mismatch the data was already written, so w
+	// must be discarded.
+	ToWriter(w io.Writer, checksums ...digest.Checksum) (int64, error)
+	// ToFile streams the body to a temporary file next to path, verified as
+	// in ToWriter, and renames it to path once complete. Nothing is left
+	// behind when it fails.
+	ToFile(path string, checksums ...digest.Checksum) error
+	// IsProblem reports whether the body is an RFC 9457 Problem Details
+	// document, served as application/problem+json.
+	IsProblem() bool
+	// ContentEncoding reports the Content-Encoding the body was sent with,
+	// even when it was decompressed before reaching the caller.
+	ContentEncoding() string
 }
 
 // ResponseFluentCookie provides access to cookies returned by the server.
 type ResponseFluentCookie interface {
 	// GetAll returns all cookies returned by the server.
 	GetAll() []*http.Cookie
+	// Get returns the first cookie named name, or nil when there is none.
+	Get(name string) *http.Cookie
+	// GetByDomain returns the cookies that apply to domain: the ones whose
+	// Domain attribute matches it or one of its parents, and the ones
+	// without Domain when domain is the host that answered.
+	GetByDomain(domain string) []*http.Cookie
 }
 
 // ResponseFluentHeader allows querying HTTP headers from the response.
@@ -1,8 +1,11 @@
 package maigo
 
 import (
+	"fmt"
 	"net/http"
+	"net/url"
 	"strings"
+	"time"
 
 	"github.com/jeanmolossi/maigo/pkg/maigo/contracts"
 )
@@ -112,6 +115,75 @@ func (c *Cookies) Unwrap() []*http.Cookie {
 	return out
 }
 
+// validateCookie reports attributes a server would not send together, such as
+// SameSite=None without Secure, or breaking the __Secure- and __Host- name
+// prefixes, as well as the ones http.Cookie.Valid rejects.
+func validateCookie(cookie *http.Cookie) error {
+	if cookie == nil {
+		return fmt.Errorf("%w: nil cookie", ErrInvalidCookie)
+	}
+
+	clone := cloneCookie(cookie)
+	clone.Name = strings.TrimSpace(clone.Name)
+
+	if err := clone.Valid(); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidCookie, err)
+	}
+
+	name := clone.Name
+
+	switch {
+	case clone.Path != "" && !strings.HasPrefix(clone.Path, "/"):
+		return fmt.Errorf("%w: %s: path %q does not start with /", ErrInvalidCookie, name, clone.Path)
+	case clone.SameSite == http.SameSiteNoneMode && !clone.Secure:
+		return fmt.Errorf("%w: %s: SameSite=None requires Secure", ErrInvalidCookie, name)
+	case strings.HasPrefix(name, "__Secure-") && !clone.Secure:
+		return fmt.Errorf("%w: %s: __Secure- prefix requires Secure", ErrInvalidCookie, name)
+	case strings.HasPrefix(name, "__Host-") && (!clone.Secure || clone.Domain != "" || clone.Path != "/"):
+		return fmt.Errorf("%w: %s: __Host- prefix requires Secure, Path=/ and no Domain", ErrInvalidCookie, name)
+	default:
+		return nil
+	}
+}
+
+// cookieApplies reports whether a client cookie is sent to u: it has not
+// expired, u is https when it is Secure, and u matches its Domain and Path.
+// Cookies without Domain are sent to every host the client talks to.
+func cookieApplies(cookie *http.Cookie, u *url.URL, now time.Time) bool {
+	if cookie.MaxAge < 0 || (!cookie.Expires.IsZero() && !cookie.Expires.After(now)) {
+		return false
+	}
+
+	if cookie.Secure && u.Scheme != "https" && u.Scheme != "wss" {
+		return false
+	}
+
+	if cookie.Domain != "" {
+		domain := strings.ToLower(strings.TrimPrefix(cookie.Domain, "."))
+		if !domainMatch(strings.ToLower(u.Hostname()), domain) {
+			return false
+		}
+	}
+
+	return cookie.Path == "" || pathMatch(u.Path, cookie.Path)
+}
+
+// pathMatch reports whether requestPath is within cookiePath, as defined by
+// RFC 6265 section 5.1.4.
+func pathMatch(requestPath, cookiePath string) bool {
+	if requestPath == "" {
+		requestPath = "/"
+	}
+
+	if !strings.HasPrefix(requestPath, cookiePath) {
+		return false
+	}
+
+	return len(requestPath) == len(cookiePath) ||
+		strings.HasSuffix(cookiePath, "/") ||
+		requestPath[len(cookiePath)] == '/'
+}
+
 // newDefaultHTTPCookies creates a Cookies instance with room for a few cookies.
 func newDefaultHTTPCookies() *Cookies {
 	return newCookiesWithCapacity(defaultCookieCap)
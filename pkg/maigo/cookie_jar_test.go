@@ -0,0 +1,195 @@
+package maigo
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/jeanmolossi/maigo/pkg/maigo/contracts"
+	"github.com/jeanmolossi/maigo/pkg/maigo/jar"
+)
+
+// serveSession sets a session cookie on /login and reports the one it
+// receives on /me.
+func serveSession(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/login":
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "mai", Path: "/", MaxAge: 3600})
+		http.Redirect(w, r, "/me", http.StatusFound)
+	case "/me":
+		cookie, err := r.Cookie("session")
+		if err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		_, _ = w.Write([]byte(cookie.Value))
+	}
+}
+
+func TestClientCookieJar_StoresResponseCookies(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer(t, serveSession)
+
+	client := NewClient(server.URL).Cookie().Jar(jar.New()).Build()
+
+	// the cookie set before the redirect is sent to its target
+	resp, err := client.GET("/login").Send()
+	if err != nil {
+		t.Fatalf("login Send() error = %v", err)
+	}
+
+	if body, _ := resp.Body().AsString(); body != "mai" {
+		t.Fatalf("login body = %q, want %q", body, "mai")
+	}
+
+	resp, err = client.GET("/me").Send()
+	if err != nil {
+		t.Fatalf("me Send() error = %v", err)
+	}
+
+	if !resp.Status().IsOK() {
+		t.Fatalf("me status = %d, want 200", resp.Status().Code())
+	}
+
+	anonymous := NewClient(server.URL).Build()
+
+	resp, err = anonymous.GET("/me").Send()
+	if err != nil {
+		t.Fatalf("anonymous Send() error = %v", err)
+	}
+
+	if resp.Status().Code() != http.StatusUnauthorized {
+		t.Fatalf("anonymous status = %d, want 401", resp.Status().Code())
+	}
+}
+
+func TestClientCookieJar_FilePersistsSession(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer(t, serveSession)
+	path := filepath.Join(t.TempDir(), "cookies.json")
+
+	cookies, err := jar.NewFile(path)
+	if err != nil {
+		t.Fatalf("NewFile() error = %v", err)
+	}
+
+	if _, err := NewClient(server.URL).Cookie().Jar(cookies).Build().GET("/login").Send(); err != nil {
+		t.Fatalf("login Send() error = %v", err)
+	}
+
+	// flush the background save, as a process does before exiting
+	if err := cookies.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	// a new process loads the session saved by the previous one
+	restored, err := jar.NewFile(path)
+	if err != nil {
+		t.Fatalf("NewFile() reload error = %v", err)
+	}
+
+	resp, err := NewClient(server.URL).
+		Config().SetTimeout(time.Second).
+		Cookie().Jar(restored).
+		Build().
+		GET("/me").
+		Send()
+	if err != nil {
+		t.Fatalf("me Send() error = %v", err)
+	}
+
+	if body, _ := resp.Body().AsString(); body != "mai" {
+		t.Fatalf("me body = %q, want %q", body, "mai")
+	}
+}
+
+func TestClientCookieJar_KeptByCustomHTTPClient(t *testing.T) {
+	t.Parallel()
+
+	cookies := jar.New()
+
+	client := NewClient("https://example.com").
+		Cookie().Jar(cookies).
+		Config().SetCustomHTTPClient(&Client{client: &http.Client{}}).
+		Build()
+
+	if got := client.(contracts.ClientCompat).Unwrap().Jar; got != cookies {
+		t.Fatalf("Jar = %v, want the jar set before", got)
+	}
+}
+
+func TestClientCookieJar_UnsupportedHTTPClient(t *testing.T) {
+	t.Parallel()
+
+	client := NewClient("https://example.com").
+		Config().SetCustomHTTPClient(doerOnly{}).
+		Cookie().Jar(jar.New()).
+		Build()
+
+	_, err := client.GET("/").Send()
+	if !errors.Is(err, ErrCookieJar) {
+		t.Fatalf("Send() error = %v, want ErrCookieJar", err)
+	}
+}
+
+// doerOnly is an HTTP client that does not expose an *http.Client.
+type doerOnly struct {
+	contracts.HTTPClient
+}
+
+func (doerOnly) Transport() http.RoundTripper { return nil }
+
+func TestResponseCookie_Lookups(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "host", Value: "1"})
+		http.SetCookie(w, &http.Cookie{Name: "site", Value: "2", Domain: "example.com"})
+		http.SetCookie(w, &http.Cookie{Name: "api", Value: "3", Domain: "api.example.com"})
+	}))
+	t.Cleanup(server.Close)
+
+	resp, err := DefaultClient(server.URL).GET("/").Send()
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	cookies := resp.Cookie()
+
+	if cookie := cookies.Get("site"); cookie == nil || cookie.Value != "2" {
+		t.Fatalf("Get(site) = %v, want site=2", cookie)
+	}
+
+	if cookie := cookies.Get("missing"); cookie != nil {
+		t.Fatalf("Get(missing) = %v, want nil", cookie)
+	}
+
+	tests := []struct {
+		domain string
+		want   []string
+	}{
+		{domain: "example.com", want: []string{"site"}},
+		{domain: "api.example.com", want: []string{"site", "api"}},
+		{domain: "API.Example.com.", want: []string{"site", "api"}},
+		{domain: "127.0.0.1", want: []string{"host"}},
+		{domain: "other.com", want: nil},
+	}
+
+	for _, tt := range tests {
+		var got []string
+		for _, cookie := range cookies.GetByDomain(tt.domain) {
+			got = append(got, cookie.Name)
+		}
+
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("GetByDomain(%q) = %v, want %v", tt.domain, got, tt.want)
+		}
+	}
+}
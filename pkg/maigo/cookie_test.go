@@ -1,9 +1,14 @@
 package maigo
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
 	"testing"
+	"time"
 )
 
 var (
@@ -351,3 +356,136 @@ func BenchmarkCookies_Unwrap(b *testing.B) {
 		})
 	}
 }
+
+func Test_validateCookie(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name   string
+		cookie *http.Cookie
+		valid  bool
+	}{
+		{"plain", &http.Cookie{Name: "session", Value: "abc"}, true},
+		{"padded name", &http.Cookie{Name: " session ", Value: "abc"}, true},
+		{"nil", nil, false},
+		{"invalid name", &http.Cookie{Name: "bad name", Value: "abc"}, false},
+		{"invalid domain", &http.Cookie{Name: "a", Value: "b", Domain: "exa mple.com"}, false},
+		{"relative path", &http.Cookie{Name: "a", Value: "b", Path: "api"}, false},
+		{"SameSite None", &http.Cookie{Name: "a", Value: "b", SameSite: http.SameSiteNoneMode}, false},
+		{"SameSite None secure", &http.Cookie{Name: "a", Value: "b", SameSite: http.SameSiteNoneMode, Secure: true}, true},
+		{"__Secure- insecure", &http.Cookie{Name: "__Secure-id", Value: "b"}, false},
+		{"__Secure- secure", &http.Cookie{Name: "__Secure-id", Value: "b", Secure: true, Domain: "example.com"}, true},
+		{"__Host- with domain", &http.Cookie{Name: "__Host-id", Value: "b", Secure: true, Path: "/", Domain: "example.com"}, false},
+		{"__Host- without path", &http.Cookie{Name: "__Host-id", Value: "b", Secure: true}, false},
+		{"__Host-", &http.Cookie{Name: "__Host-id", Value: "b", Secure: true, Path: "/"}, true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := validateCookie(tc.cookie)
+			if (err == nil) != tc.valid {
+				t.Fatalf("validateCookie() error = %v, want valid %v", err, tc.valid)
+			}
+
+			if err != nil && !errors.Is(err, ErrInvalidCookie) {
+				t.Fatalf("validateCookie() error = %v, want ErrInvalidCookie", err)
+			}
+		})
+	}
+}
+
+func Test_cookieApplies(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+
+	cases := []struct {
+		name    string
+		cookie  *http.Cookie
+		url     string
+		applies bool
+	}{
+		{"no scope", &http.Cookie{Name: "a"}, "http://any.example/x", true},
+		{"expired", &http.Cookie{Name: "a", Expires: now.Add(-time.Second)}, "http://example.com/", false},
+		{"not expired", &http.Cookie{Name: "a", Expires: now.Add(time.Hour)}, "http://example.com/", true},
+		{"negative max age", &http.Cookie{Name: "a", MaxAge: -1}, "http://example.com/", false},
+		{"secure over http", &http.Cookie{Name: "a", Secure: true}, "http://example.com/", false},
+		{"secure over https", &http.Cookie{Name: "a", Secure: true}, "https://example.com/", true},
+		{"domain", &http.Cookie{Name: "a", Domain: "example.com"}, "http://example.com/", true},
+		{"subdomain", &http.Cookie{Name: "a", Domain: ".Example.com"}, "http://api.example.com:8080/", true},
+		{"other domain", &http.Cookie{Name: "a", Domain: "example.com"}, "http://example.org/", false},
+		{"suffix only", &http.Cookie{Name: "a", Domain: "example.com"}, "http://badexample.com/", false},
+		{"path", &http.Cookie{Name: "a", Path: "/api"}, "http://example.com/api", true},
+		{"sub path", &http.Cookie{Name: "a", Path: "/api"}, "http://example.com/api/users", true},
+		{"sibling path", &http.Cookie{Name: "a", Path: "/api"}, "http://example.com/apiv2", false},
+		{"outside path", &http.Cookie{Name: "a", Path: "/api/"}, "http://example.com/", false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			u, err := url.Parse(tc.url)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := cookieApplies(tc.cookie, u, now); got != tc.applies {
+				t.Fatalf("cookieApplies(%v, %s) = %v, want %v", tc.cookie, tc.url, got, tc.applies)
+			}
+		})
+	}
+}
+
+func TestClientCookieBuilder_ScopesCookies(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var names []string
+		for _, cookie := range r.Cookies() {
+			names = append(names, cookie.Name)
+		}
+
+		w.Header()["X-Cookies"] = names
+	}))
+	t.Cleanup(server.Close)
+
+	client := NewClient(server.URL).
+		Cookie().Add(&http.Cookie{Name: "everywhere", Value: "1"}).
+		Cookie().Add(&http.Cookie{Name: "api", Value: "1", Path: "/api"}).
+		Cookie().Add(&http.Cookie{Name: "local", Value: "1", Domain: "127.0.0.1"}).
+		Cookie().Add(&http.Cookie{Name: "remote", Value: "1", Domain: "example.com"}).
+		Cookie().Add(&http.Cookie{Name: "secure", Value: "1", Secure: true}).
+		Cookie().Add(&http.Cookie{Name: "gone", Value: "1", Expires: time.Now().Add(-time.Minute)}).
+		Cookie().Add(&http.Cookie{Name: "fresh", Value: "1", MaxAge: 60}).
+		Build()
+
+	tests := []struct {
+		path string
+		want []string
+	}{
+		{path: "/", want: []string{"everywhere", "local", "fresh"}},
+		{path: "/api/users", want: []string{"everywhere", "api", "local", "fresh"}},
+	}
+
+	for _, tt := range tests {
+		resp, err := client.GET(tt.path).Send()
+		if err != nil {
+			t.Fatalf("GET %s error = %v", tt.path, err)
+		}
+
+		if got := resp.Raw().Header.Values("X-Cookies"); !slices.Equal(got, tt.want) {
+			t.Errorf("GET %s cookies = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+
+	invalid := NewClient(server.URL).
+		Cookie().Add(&http.Cookie{Name: "__Host-id", Value: "1", Domain: "example.com"}).
+		Build()
+
+	if _, err := invalid.GET("/").Send(); !errors.Is(err, ErrInvalidCookie) {
+		t.Fatalf("Send() error = %v, want ErrInvalidCookie", err)
+	}
+}
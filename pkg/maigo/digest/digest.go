@@ -0,0 +1,199 @@
+// Package digest verifies downloaded content against checksums supplied by
+// the caller or announced by the server in the Content-Digest and
+// Repr-Digest (RFC 9530) or Content-MD5 headers.
+package digest
+
+import (
+	"bytes"
+	"crypto/md5" //nolint:gosec // Content-MD5 is an integrity check, not a security boundary.
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"net/http"
+	"strings"
+)
+
+// Algorithm names a hash algorithm as registered in the IANA Hash Algorithms
+// for HTTP Digest Fields registry.
+type Algorithm string
+
+const (
+	SHA256 Algorithm = "sha-256"
+	SHA512 Algorithm = "sha-512"
+	MD5    Algorithm = "md5"
+)
+
+var (
+	ErrUnsupportedAlgorithm = errors.New("unsupported digest algorithm")
+	ErrInvalidChecksum      = errors.New("invalid checksum")
+	ErrChecksumMismatch     = errors.New("checksum mismatch")
+)
+
+// Supported reports whether the algorithm can be computed.
+func (a Algorithm) Supported() bool {
+	switch a {
+	case SHA256, SHA512, MD5:
+		return true
+	default:
+		return false
+	}
+}
+
+// String returns the algorithm name.
+func (a Algorithm) String() string {
+	return string(a)
+}
+
+func (a Algorithm) new() hash.Hash {
+	switch a {
+	case SHA256:
+		return sha256.New()
+	case SHA512:
+		return sha512.New()
+	case MD5:
+		return md5.New() //nolint:gosec // see import
+	default:
+		return nil
+	}
+}
+
+// Checksum is the expected digest of some content.
+type Checksum struct {
+	Algorithm Algorithm
+	Sum       []byte
+}
+
+// ParseHex builds a checksum from a hex encoded sum, the format usually
+// published next to release artifacts.
+func ParseHex(algorithm Algorithm, sum string) (Checksum, error) {
+	if !algorithm.Supported() {
+		return Checksum{}, fmt.Errorf("%w: %q", ErrUnsupportedAlgorithm, algorithm)
+	}
+
+	raw, err := hex.DecodeString(strings.TrimSpace(sum))
+	if err != nil {
+		return Checksum{}, fmt.Errorf("%w: %w", ErrInvalidChecksum, err)
+	}
+
+	return Checksum{Algorithm: algorithm, Sum: raw}, nil
+}
+
+// String formats the checksum as algorithm:hex.
+func (c Checksum) String() string {
+	return c.Algorithm.String() + ":" + hex.EncodeToString(c.Sum)
+}
+
+// ContentDigests returns the checksums of the message content announced by
+// the Content-Digest and Content-MD5 headers. For a 206 Partial Content
+// response they cover only the part received. Unsupported algorithms are
+// skipped.
+func ContentDigests(h http.Header) []Checksum {
+	checksums := parseDigestField(h.Values("Content-Digest"))
+
+	if value := strings.TrimSpace(h.Get("Content-MD5")); value != "" {
+		if sum, err := base64.StdEncoding.DecodeString(value); err == nil {
+			checksums = append(checksums, Checksum{Algorithm: MD5, Sum: sum})
+		}
+	}
+
+	return checksums
+}
+
+// ReprDigests returns the checksums of the whole representation announced
+// by the Repr-Digest header, which also hold for a range of it. Unsupported
+// algorithms are skipped.
+func ReprDigests(h http.Header) []Checksum {
+	return parseDigestField(h.Values("Repr-Digest"))
+}
+
+// parseDigestField parses dictionary members such as sha-256=:base64:.
+func parseDigestField(values []string) []Checksum {
+	var checksums []Checksum
+
+	for _, value := range values {
+		for member := range strings.SplitSeq(value, ",") {
+			key, sum, ok := strings.Cut(strings.TrimSpace(member), "=")
+			if !ok {
+				continue
+			}
+
+			algorithm := Algorithm(strings.ToLower(strings.TrimSpace(key)))
+			if !algorithm.Supported() {
+				continue
+			}
+
+			// parameters are not defined for digest fields
+			sum, _, _ = strings.Cut(sum, ";")
+			sum = strings.TrimSpace(sum)
+
+			if len(sum) < 2 || sum[0] != ':' || sum[len(sum)-1] != ':' {
+				continue
+			}
+
+			raw, err := base64.StdEncoding.DecodeString(sum[1 : len(sum)-1])
+			if err != nil {
+				continue
+			}
+
+			checksums = append(checksums, Checksum{Algorithm: algorithm, Sum: raw})
+		}
+	}
+
+	return checksums
+}
+
+// Verifier computes the digests of the content written to it, one hash per
+// algorithm, and compares them with the expected checksums.
+type Verifier struct {
+	hashes    map[Algorithm]hash.Hash
+	checksums []Checksum
+}
+
+// NewVerifier creates a verifier for checksums. Without checksums it accepts
+// any content.
+func NewVerifier(checksums ...Checksum) (*Verifier, error) {
+	v := &Verifier{hashes: map[Algorithm]hash.Hash{}}
+
+	for _, checksum := range checksums {
+		if !checksum.Algorithm.Supported() {
+			return nil, fmt.Errorf("%w: %q", ErrUnsupportedAlgorithm, checksum.Algorithm)
+		}
+
+		if _, ok := v.hashes[checksum.Algorithm]; !ok {
+			v.hashes[checksum.Algorithm] = checksum.Algorithm.new()
+		}
+
+		v.checksums = append(v.checksums, checksum)
+	}
+
+	return v, nil
+}
+
+// Write implements io.Writer.
+func (v *Verifier) Write(p []byte) (int, error) {
+	for _, h := range v.hashes {
+		h.Write(p)
+	}
+
+	return len(p), nil
+}
+
+// Verify reports an ErrChecksumMismatch for every checksum that does not
+// match the content written so far.
+func (v *Verifier) Verify() error {
+	var errs []error
+
+	for _, checksum := range v.checksums {
+		got := v.hashes[checksum.Algorithm].Sum(nil)
+		if !bytes.Equal(got, checksum.Sum) {
+			errs = append(errs, fmt.Errorf("%w: %s: got %x, want %x",
+				ErrChecksumMismatch, checksum.Algorithm, got, checksum.Sum))
+		}
+	}
+
+	return errors.Join(errs...)
+}
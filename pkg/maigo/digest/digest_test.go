@@ -0,0 +1,92 @@
+package digest
+
+import (
+	"crypto/md5" //nolint:gosec // testing Content-MD5
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+const content = "hello, world"
+
+func TestParseHex(t *testing.T) {
+	t.Parallel()
+
+	sum := sha256.Sum256([]byte(content))
+
+	checksum, err := ParseHex(SHA256, hex.EncodeToString(sum[:]))
+	if err != nil {
+		t.Fatalf("ParseHex() error = %v", err)
+	}
+
+	if checksum.String() != "sha-256:"+hex.EncodeToString(sum[:]) {
+		t.Fatalf("String() = %q", checksum.String())
+	}
+
+	if _, err := ParseHex("crc32", "00"); !errors.Is(err, ErrUnsupportedAlgorithm) {
+		t.Fatalf("ParseHex(crc32) error = %v, want %v", err, ErrUnsupportedAlgorithm)
+	}
+
+	if _, err := ParseHex(SHA256, "zz"); !errors.Is(err, ErrInvalidChecksum) {
+		t.Fatalf("ParseHex(zz) error = %v, want %v", err, ErrInvalidChecksum)
+	}
+}
+
+func TestHeaderDigests(t *testing.T) {
+	t.Parallel()
+
+	sha256Sum := sha256.Sum256([]byte(content))
+	sha512Sum := sha512.Sum512([]byte(content))
+	md5Sum := md5.Sum([]byte(content)) //nolint:gosec // testing Content-MD5
+
+	h := http.Header{}
+	h.Set("Content-Digest", "sha-256=:"+base64.StdEncoding.EncodeToString(sha256Sum[:])+":, unixsum=:AAA=:, broken")
+	h.Set("Content-MD5", base64.StdEncoding.EncodeToString(md5Sum[:]))
+	h.Set("Repr-Digest", "SHA-512=:"+base64.StdEncoding.EncodeToString(sha512Sum[:])+":")
+
+	contentDigests := ContentDigests(h)
+	if len(contentDigests) != 2 || contentDigests[0].Algorithm != SHA256 || contentDigests[1].Algorithm != MD5 {
+		t.Fatalf("ContentDigests() = %v, want sha-256 and md5", contentDigests)
+	}
+
+	reprDigests := ReprDigests(h)
+	if len(reprDigests) != 1 || reprDigests[0].Algorithm != SHA512 {
+		t.Fatalf("ReprDigests() = %v, want sha-512", reprDigests)
+	}
+
+	verifier, err := NewVerifier(append(contentDigests, reprDigests...)...)
+	if err != nil {
+		t.Fatalf("NewVerifier() error = %v", err)
+	}
+
+	_, _ = verifier.Write([]byte(content))
+
+	if err := verifier.Verify(); err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+}
+
+func TestVerifier_Mismatch(t *testing.T) {
+	t.Parallel()
+
+	sum := sha256.Sum256([]byte(content))
+
+	verifier, err := NewVerifier(Checksum{Algorithm: SHA256, Sum: sum[:]})
+	if err != nil {
+		t.Fatalf("NewVerifier() error = %v", err)
+	}
+
+	_, _ = verifier.Write([]byte("hello, there"))
+
+	if err := verifier.Verify(); !errors.Is(err, ErrChecksumMismatch) {
+		t.Fatalf("Verify() error = %v, want %v", err, ErrChecksumMismatch)
+	}
+
+	if _, err := NewVerifier(Checksum{Algorithm: "crc32"}); !errors.Is(err, ErrUnsupportedAlgorithm) {
+		t.Fatalf("NewVerifier(crc32) error = %v, want %v", err, ErrUnsupportedAlgorithm)
+	}
+}
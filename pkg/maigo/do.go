@@ -0,0 +1,144 @@
+package maigo
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/jeanmolossi/maigo/pkg/maigo/contracts"
+	"github.com/jeanmolossi/maigo/pkg/maigo/header"
+	"github.com/jeanmolossi/maigo/pkg/maigo/mime"
+)
+
+// Do sends b and decodes the response body into T when the status is 2xx,
+// or into the Body of an HTTPError[E] otherwise. Bodies are decoded with the
+// codec registered for the response Content-Type, JSON when there is none;
+// []byte and string receive the raw body, and an empty body leaves the zero
+// value. The body is always closed.
+//
+// The error reports failures to send the request or decode the success
+// body; a non-2xx response is reported through the HTTPError alone, even
+// when ExpectSuccess is set.
+//
+// Example:
+//
+//	user, apiErr, err := maigo.Do[User, APIError](client.GET("/users/1"))
+//	switch {
+//	case err != nil:
+//	    return err
+//	case apiErr != nil:
+//	    return fmt.Errorf("%s: %w", apiErr.Body.Message, apiErr)
+//	}
+func Do[T, E any](b contracts.RequestBuilder) (T, *HTTPError[E], error) {
+	var zero T
+
+	// the error payload is decoded here, even with ExpectSuccess
+	resp, err := b.SendAnyStatus()
+	if err != nil {
+		return zero, nil, err
+	}
+
+	defer resp.Body().Close()
+
+	if !resp.Status().Is2xxSuccessful() {
+		data := readErrorBody(resp, maxErrorBody)
+		httpErr := newHTTPError[E](resp, data)
+
+		// the status is what matters, a payload that is not understood
+		// leaves the zero value and can be inspected through the snippet
+		_ = decodeResponseBytes(resp, data, &httpErr.Body)
+
+		return zero, httpErr, nil
+	}
+
+	var v T
+	if err := decodeResponse(resp, &v); err != nil {
+		return zero, nil, err
+	}
+
+	return v, nil, nil
+}
+
+// decodeResponseBytes decodes data, read from the body of resp, into v
+// as decodeResponse does.
+func decodeResponseBytes(resp contracts.Response, data []byte, v any) error {
+	switch out := v.(type) {
+	case *[]byte:
+		*out = data
+		return nil
+	case *string:
+		*out = string(data)
+		return nil
+	}
+
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil
+	}
+
+	mediaType := mime.Type(resp.Header().Get(header.ContentType.String()))
+	if mediaType == "" {
+		mediaType = mime.JSON
+	}
+
+	codecs := contracts.Codecs(builtinCodecs)
+	if body, ok := resp.Body().(*ResponseBody); ok && body.codecs != nil {
+		codecs = body.codecs
+	}
+
+	codec, ok := codecs.Lookup(mediaType)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnsupportedMediaType, mediaType)
+	}
+
+	if err := codec.Decode(bytes.NewReader(data), v); err != nil {
+		return errors.Join(ErrToDecodeBody, err)
+	}
+
+	return nil
+}
+
+// decodeResponse decodes the body of resp into v with the codec of its
+// Content-Type.
+func decodeResponse(resp contracts.Response, v any) error {
+	switch out := v.(type) {
+	case *[]byte:
+		data, err := io.ReadAll(responseReader(resp))
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrToDecodeBody, err)
+		}
+
+		*out = data
+
+		return nil
+	case *string:
+		data, err := io.ReadAll(responseReader(resp))
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrToDecodeBody, err)
+		}
+
+		*out = string(data)
+
+		return nil
+	}
+
+	var err error
+
+	if body, ok := resp.Body().(*ResponseBody); ok {
+		mediaType := mime.Type(body.contentType)
+		if mediaType == "" {
+			mediaType = mime.JSON
+		}
+
+		err = body.decodeAs(mediaType, v)
+	} else {
+		err = resp.Body().Decode(v)
+	}
+
+	// an empty body leaves the zero value
+	if errors.Is(err, io.EOF) {
+		return nil
+	}
+
+	return err
+}
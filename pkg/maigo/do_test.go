@@ -0,0 +1,165 @@
+package maigo
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/jeanmolossi/maigo/pkg/httpx"
+)
+
+type doUser struct {
+	Name string `json:"name" yaml:"name"`
+}
+
+type doAPIError struct {
+	Message string `json:"message"`
+}
+
+type closeCounter struct {
+	io.ReadCloser
+	closed *atomic.Int32
+}
+
+func (c *closeCounter) Close() error {
+	c.closed.Add(1)
+	return c.ReadCloser.Close()
+}
+
+// countCloses counts the response bodies closed.
+func countCloses(closed *atomic.Int32) httpx.ChainedRoundTripper {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return httpx.RoundTripperFn(func(r *http.Request) (*http.Response, error) {
+			resp, err := next.RoundTrip(r)
+			if err == nil {
+				resp.Body = &closeCounter{ReadCloser: resp.Body, closed: closed}
+			}
+
+			return resp, err
+		})
+	}
+}
+
+func TestDo_Success(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+	}{
+		{name: "json", contentType: "application/json; charset=utf-8", body: `{"name":"mai"}`},
+		{name: "yaml", contentType: "application/yaml", body: "name: mai\n"},
+		{name: "no content type", body: `{"name":"mai"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var closed atomic.Int32
+
+			server := newTestServer(t, respond(http.StatusOK, tt.body, "Content-Type", tt.contentType))
+			client := NewClient(server.URL).Use(countCloses(&closed)).Build()
+
+			user, apiErr, err := Do[doUser, doAPIError](client.GET("/"))
+			if err != nil || apiErr != nil {
+				t.Fatalf("Do() error = %v, apiErr = %v", err, apiErr)
+			}
+
+			if user.Name != "mai" {
+				t.Fatalf("user = %+v, want mai", user)
+			}
+
+			if closed.Load() == 0 {
+				t.Fatal("body not closed")
+			}
+		})
+	}
+}
+
+func TestDo_ErrorPayload(t *testing.T) {
+	t.Parallel()
+
+	var closed atomic.Int32
+
+	server := newTestServer(t, respond(http.StatusNotFound, `{"message":"no such user"}`, "Content-Type", "application/json"))
+	client := NewClient(server.URL).Use(countCloses(&closed)).Build()
+
+	user, apiErr, err := Do[doUser, doAPIError](client.GET("/"))
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+
+	if apiErr == nil || apiErr.StatusCode != http.StatusNotFound || apiErr.Body.Message != "no such user" {
+		t.Fatalf("apiErr = %+v, want 404 with the payload", apiErr)
+	}
+
+	if !errors.Is(apiErr, ErrUnexpectedStatus) {
+		t.Fatalf("errors.Is(apiErr, ErrUnexpectedStatus) = false")
+	}
+
+	if user != (doUser{}) || closed.Load() == 0 {
+		t.Fatalf("user = %+v, closed = %d", user, closed.Load())
+	}
+}
+
+func TestDo_UndecodableErrorPayload(t *testing.T) {
+	t.Parallel()
+
+	client := DefaultClient(newTestServer(t, respond(http.StatusBadGateway, "<h1>bad gateway</h1>", "Content-Type", "text/html")).URL)
+
+	_, apiErr, err := Do[doUser, doAPIError](client.GET("/"))
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+
+	if apiErr == nil || apiErr.StatusCode != http.StatusBadGateway || apiErr.Body != (doAPIError{}) {
+		t.Fatalf("apiErr = %+v, want 502 without payload", apiErr)
+	}
+
+	// raw error bodies keep whatever was sent
+	_, rawErr, _ := Do[doUser, string](client.GET("/"))
+	if rawErr == nil || !strings.Contains(rawErr.Body, "bad gateway") {
+		t.Fatalf("rawErr = %+v, want the html body", rawErr)
+	}
+}
+
+func TestDo_EmptyAndRawBodies(t *testing.T) {
+	t.Parallel()
+
+	client := DefaultClient(newTestServer(t, respond(http.StatusNoContent, "", "Content-Type", "")).URL)
+
+	user, apiErr, err := Do[doUser, doAPIError](client.GET("/"))
+	if err != nil || apiErr != nil || user != (doUser{}) {
+		t.Fatalf("Do() = %+v, %v, %v, want the zero value", user, apiErr, err)
+	}
+
+	client = DefaultClient(newTestServer(t, respond(http.StatusOK, "pong", "Content-Type", "text/plain")).URL)
+
+	text, _, err := Do[string, doAPIError](client.GET("/"))
+	if err != nil || text != "pong" {
+		t.Fatalf("Do() = %q, %v, want pong", text, err)
+	}
+}
+
+func TestDo_DecodeError(t *testing.T) {
+	t.Parallel()
+
+	var closed atomic.Int32
+
+	server := newTestServer(t, respond(http.StatusOK, `{"name":`, "Content-Type", "application/json"))
+	client := NewClient(server.URL).Use(countCloses(&closed)).Build()
+
+	_, _, err := Do[doUser, doAPIError](client.GET("/"))
+	if !errors.Is(err, ErrToDecodeBody) {
+		t.Fatalf("Do() error = %v, want %v", err, ErrToDecodeBody)
+	}
+
+	if closed.Load() == 0 {
+		t.Fatal("body not closed")
+	}
+}
@@ -0,0 +1,403 @@
+package maigo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/jeanmolossi/maigo/pkg/maigo/contracts"
+	"github.com/jeanmolossi/maigo/pkg/maigo/digest"
+	"github.com/jeanmolossi/maigo/pkg/maigo/header"
+)
+
+const (
+	partSuffix        = ".part"
+	validatorSuffix   = ".validator"
+	filePerm          = 0o644
+	defaultMaxResumes = 3
+)
+
+// DownloadConfig contains settings for Download. The zero value is ready to
+// use.
+type DownloadConfig struct {
+	// Checksums the downloaded file must match, in addition to the digests
+	// announced by the server.
+	Checksums []digest.Checksum
+	// MaxResumes bounds how many times an interrupted transfer is resumed
+	// within a call. Defaults to 3; a negative value disables resuming.
+	MaxResumes int
+}
+
+// Download sends req and streams the response body to path. The data is
+// written to path + ".part" and renamed into place once complete and
+// verified, as in ResponseBody.ToFile.
+//
+// When the transfer is interrupted, it is resumed with a Range request
+// guarded by If-Range, so a resource that changed meanwhile is downloaded
+// again from the start. A partial file left by a previous call is resumed
+// the same way. Each request is sent from a clone of req, so req is left
+// untouched.
+//
+// A file failing verification is removed.
+//
+// Example:
+//
+//	sum, _ := digest.ParseHex(digest.SHA256, "9f86d08...")
+//	err := maigo.Download(client.GET("/releases/app.tar.gz"), "app.tar.gz",
+//	    maigo.DownloadConfig{Checksums: []digest.Checksum{sum}})
+func Download(req contracts.RequestBuilder, path string, cfg DownloadConfig) error {
+	if cfg.MaxResumes == 0 {
+		cfg.MaxResumes = defaultMaxResumes
+	}
+
+	d := &download{req: req, path: path, cfg: cfg}
+
+	if err := d.open(); err != nil {
+		return err
+	}
+
+	if err := d.transfer(); err != nil {
+		// the partial file is kept to be resumed later, unless it is empty
+		_ = d.file.Close()
+
+		if d.offset == 0 {
+			d.remove()
+		}
+
+		return err
+	}
+
+	if err := d.verify(); err != nil {
+		_ = d.file.Close()
+		d.remove()
+
+		return err
+	}
+
+	if err := commitFile(d.file, path); err != nil {
+		return err
+	}
+
+	_ = os.Remove(d.validatorPath())
+
+	return nil
+}
+
+// interruptedError marks failures the transfer can be resumed from.
+type interruptedError struct {
+	err error
+}
+
+func (e *interruptedError) Error() string { return e.err.Error() }
+
+func (e *interruptedError) Unwrap() error { return e.err }
+
+type download struct {
+	req  contracts.RequestBuilder
+	path string
+	cfg  DownloadConfig
+
+	file   *os.File
+	offset int64
+	// validator is the strong ETag or Last-Modified date the partial file
+	// was downloaded with, sent as If-Range.
+	validator string
+	// announced holds the digests of the whole file announced by the server.
+	announced []digest.Checksum
+}
+
+func (d *download) partPath() string {
+	return d.path + partSuffix
+}
+
+func (d *download) validatorPath() string {
+	return d.partPath() + validatorSuffix
+}
+
+// open opens the partial file, resuming from its end.
+func (d *download) open() error {
+	file, err := os.OpenFile(d.partPath(), os.O_RDWR|os.O_CREATE, filePerm)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrToWriteFile, err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return fmt.Errorf("%w: %w", ErrToWriteFile, err)
+	}
+
+	d.file = file
+	d.offset = info.Size()
+
+	if validator, err := os.ReadFile(d.validatorPath()); err == nil {
+		d.validator = strings.TrimSpace(string(validator))
+	}
+
+	return nil
+}
+
+// transfer fetches the file, resuming it after interruptions.
+func (d *download) transfer() error {
+	for attempt := 0; ; attempt++ {
+		err := d.fetch()
+		if err == nil {
+			return nil
+		}
+
+		var interrupted *interruptedError
+		if !errors.As(err, &interrupted) || attempt >= d.cfg.MaxResumes {
+			return err
+		}
+	}
+}
+
+// fetch requests the remaining bytes of the file and appends them to the
+// partial file.
+func (d *download) fetch() error {
+	// without a validator the partial file may belong to another version
+	if d.offset > 0 && d.validator == "" {
+		if err := d.truncate(); err != nil {
+			return err
+		}
+	}
+
+	req := d.req.Clone()
+
+	if d.offset > 0 {
+		req.Header().Set(header.Range, fmt.Sprintf("bytes=%d-", d.offset))
+
+		if d.validator != "" {
+			req.Header().Set(header.IfRange, d.validator)
+		}
+	}
+
+	resp, err := req.SendAnyStatus()
+	if err != nil {
+		// a client error reported by an interceptor is not retried
+		if isContextError(err) || isClientError(err) {
+			return err
+		}
+
+		return &interruptedError{err: err}
+	}
+
+	defer resp.Body().Close()
+
+	status := resp.Status()
+	contentRange := resp.Header().Get(header.ContentRange.String())
+
+	switch {
+	case status.IsPartialContent():
+		start, _, ok := parseContentRange(contentRange)
+		if !ok || start != d.offset {
+			if err := d.truncate(); err != nil {
+				return err
+			}
+
+			return &interruptedError{err: fmt.Errorf("%w: unexpected Content-Range %q", ErrDownloadStatus, contentRange)}
+		}
+	case status.IsRequestedRangeNotSatisfiable() && d.offset > 0:
+		// the partial file may already hold the whole resource
+		if _, size, ok := parseContentRange(contentRange); ok && size == d.offset {
+			return nil
+		}
+
+		if err := d.truncate(); err != nil {
+			return err
+		}
+
+		return &interruptedError{err: fmt.Errorf("%w: %d %s", ErrDownloadStatus, status.Code(), status.Text())}
+	case status.IsOK():
+		if err := d.truncate(); err != nil {
+			return err
+		}
+
+		if err := d.saveValidator(resp); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("%w: %d %s", ErrDownloadStatus, status.Code(), status.Text())
+	}
+
+	if _, err := d.file.Seek(d.offset, io.SeekStart); err != nil {
+		return fmt.Errorf("%w: %w", ErrToWriteFile, err)
+	}
+
+	body := &readErrorReader{reader: responseReader(resp)}
+
+	n, err := io.Copy(d.file, body)
+	d.offset += n
+
+	if err != nil {
+		if body.err != nil && !isContextError(body.err) {
+			return &interruptedError{err: body.err}
+		}
+
+		if body.err != nil {
+			return body.err
+		}
+
+		return fmt.Errorf("%w: %w", ErrToWriteFile, err)
+	}
+
+	if responseBody, ok := resp.Body().(*ResponseBody); ok {
+		d.announced = responseBody.digests(true)
+	}
+
+	return nil
+}
+
+// truncate discards the partial file.
+func (d *download) truncate() error {
+	if err := d.file.Truncate(0); err != nil {
+		return fmt.Errorf("%w: %w", ErrToWriteFile, err)
+	}
+
+	d.offset = 0
+	d.announced = nil
+
+	return nil
+}
+
+// saveValidator records the validator of resp next to the partial file, so
+// a later call can resume it.
+func (d *download) saveValidator(resp contracts.Response) error {
+	d.validator = ""
+
+	if etag := resp.Header().Get(header.ETag.String()); etag != "" && !strings.HasPrefix(etag, "W/") {
+		d.validator = etag
+	} else if lastModified := resp.Header().Get(header.LastModified.String()); lastModified != "" {
+		d.validator = lastModified
+	}
+
+	if d.validator == "" {
+		_ = os.Remove(d.validatorPath())
+		return nil
+	}
+
+	if err := os.WriteFile(d.validatorPath(), []byte(d.validator), filePerm); err != nil {
+		return fmt.Errorf("%w: %w", ErrToWriteFile, err)
+	}
+
+	return nil
+}
+
+// verify checks the complete file against the expected checksums.
+func (d *download) verify() error {
+	checksums := slices.Concat(d.cfg.Checksums, d.announced)
+	if len(checksums) == 0 {
+		return nil
+	}
+
+	verifier, err := digest.NewVerifier(checksums...)
+	if err != nil {
+		return err
+	}
+
+	if _, err := d.file.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("%w: %w", ErrToWriteFile, err)
+	}
+
+	if _, err := io.Copy(verifier, d.file); err != nil {
+		return fmt.Errorf("%w: %w", ErrToWriteFile, err)
+	}
+
+	return verifier.Verify()
+}
+
+// remove deletes the partial file and its validator.
+func (d *download) remove() {
+	_ = os.Remove(d.partPath())
+	_ = os.Remove(d.validatorPath())
+}
+
+// commitFile flushes file to disk and atomically renames it to path.
+func commitFile(file *os.File, path string) error {
+	err := file.Sync()
+
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err == nil {
+		err = os.Rename(file.Name(), path)
+	}
+
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrToWriteFile, err)
+	}
+
+	return nil
+}
+
+// parseContentRange parses "bytes start-end/size" and "bytes */size". An
+// unknown size is reported as -1.
+func parseContentRange(value string) (start, size int64, ok bool) {
+	value, ok = strings.CutPrefix(strings.TrimSpace(value), "bytes ")
+	if !ok {
+		return 0, 0, false
+	}
+
+	span, total, ok := strings.Cut(value, "/")
+	if !ok {
+		return 0, 0, false
+	}
+
+	size = -1
+
+	if total != "*" {
+		var err error
+		if size, err = strconv.ParseInt(total, 10, 64); err != nil {
+			return 0, 0, false
+		}
+	}
+
+	if span == "*" {
+		return 0, size, true
+	}
+
+	first, _, ok := strings.Cut(span, "-")
+	if !ok {
+		return 0, 0, false
+	}
+
+	start, err := strconv.ParseInt(first, 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+
+	return start, size, true
+}
+
+// readErrorReader records the error of the underlying reader, telling an
+// interrupted body from a failing write.
+type readErrorReader struct {
+	reader io.Reader
+	err    error
+}
+
+func (r *readErrorReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	if err != nil && !errors.Is(err, io.EOF) {
+		r.err = err
+	}
+
+	return n, err
+}
+
+func isContextError(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// isClientError reports whether err is an HTTPError with a 4xx status.
+func isClientError(err error) bool {
+	var httpErr *HTTPError[[]byte]
+
+	return errors.As(err, &httpErr) && httpErr.StatusCode >= 400 && httpErr.StatusCode < 500
+}
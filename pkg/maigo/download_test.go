@@ -0,0 +1,298 @@
+package maigo
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jeanmolossi/maigo/pkg/maigo/digest"
+)
+
+var artifact = []byte(strings.Repeat("0123456789abcdef", 4096))
+
+func sha256Checksum(t *testing.T, data []byte) digest.Checksum {
+	t.Helper()
+
+	sum := sha256.Sum256(data)
+
+	checksum, err := digest.ParseHex(digest.SHA256, hex.EncodeToString(sum[:]))
+	if err != nil {
+		t.Fatalf("ParseHex() error = %v", err)
+	}
+
+	return checksum
+}
+
+func reprDigest(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha-256=:" + base64.StdEncoding.EncodeToString(sum[:]) + ":"
+}
+
+// serveArtifact serves data with an ETag and Repr-Digest, honouring Range
+// and If-Range.
+func serveArtifact(data []byte, etag string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Repr-Digest", reprDigest(data))
+		http.ServeContent(w, r, "artifact", time.Time{}, bytes.NewReader(data))
+	}
+}
+
+// abortArtifact is like serveArtifact, but aborts the response halfway.
+func abortArtifact(data []byte, etag string) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Repr-Digest", reprDigest(data))
+		w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+		_, _ = w.Write(data[:len(data)/2])
+		w.(http.Flusher).Flush()
+
+		panic(http.ErrAbortHandler)
+	}
+}
+
+func assertFile(t *testing.T, path string, want []byte) {
+	t.Helper()
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+
+	if !bytes.Equal(got, want) {
+		t.Fatalf("file has %d bytes, want %d matching bytes", len(got), len(want))
+	}
+
+	leftovers, _ := filepath.Glob(path + ".*")
+	hidden, _ := filepath.Glob(filepath.Join(filepath.Dir(path), ".*"+partSuffix))
+
+	if len(leftovers)+len(hidden) > 0 {
+		t.Fatalf("temporary files left behind: %v %v", leftovers, hidden)
+	}
+}
+
+func TestResponseBody_ToFile(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer(t, serveArtifact(artifact, `"v1"`))
+	path := filepath.Join(t.TempDir(), "artifact.bin")
+
+	resp, err := DefaultClient(server.URL).GET("/").Send()
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	if err := resp.Body().ToFile(path, sha256Checksum(t, artifact)); err != nil {
+		t.Fatalf("ToFile() error = %v", err)
+	}
+
+	assertFile(t, path, artifact)
+}
+
+func TestResponseBody_ToFileMismatch(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Repr-Digest", reprDigest([]byte("other")))
+		_, _ = w.Write(artifact)
+	}))
+	t.Cleanup(server.Close)
+
+	dir := t.TempDir()
+
+	resp, err := DefaultClient(server.URL).GET("/").Send()
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	err = resp.Body().ToFile(filepath.Join(dir, "artifact.bin"))
+	if !errors.Is(err, digest.ErrChecksumMismatch) {
+		t.Fatalf("ToFile() error = %v, want %v", err, digest.ErrChecksumMismatch)
+	}
+
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Fatalf("files left behind: %v", entries)
+	}
+}
+
+func TestResponseBody_ToWriter(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer(t, serveArtifact(artifact, `"v1"`))
+
+	resp, err := DefaultClient(server.URL).GET("/").Send()
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	var buf bytes.Buffer
+
+	n, err := resp.Body().ToWriter(&buf, sha256Checksum(t, []byte("other")))
+	if !errors.Is(err, digest.ErrChecksumMismatch) {
+		t.Fatalf("ToWriter() error = %v, want %v", err, digest.ErrChecksumMismatch)
+	}
+
+	if n != int64(len(artifact)) || !bytes.Equal(buf.Bytes(), artifact) {
+		t.Fatalf("ToWriter() wrote %d bytes, want %d", n, len(artifact))
+	}
+}
+
+func TestDownload_ResumesInterruptedTransfer(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer(t, inTurn(abortArtifact(artifact, `"v1"`), serveArtifact(artifact, `"v1"`)))
+	path := filepath.Join(t.TempDir(), "artifact.bin")
+	req := DefaultClient(server.URL).GET("/")
+
+	err := Download(req, path, DownloadConfig{
+		Checksums: []digest.Checksum{sha256Checksum(t, artifact)},
+	})
+	if err != nil {
+		t.Fatalf("Download() error = %v", err)
+	}
+
+	assertFile(t, path, artifact)
+
+	want := []string{"", "bytes=" + strconv.Itoa(len(artifact)/2) + "-"}
+	if got := server.Header("Range"); len(got) != 2 || got[0] != want[0] || got[1] != want[1] {
+		t.Fatalf("Range headers = %q, want %q", got, want)
+	}
+
+	// the resumed request was sent from a clone
+	unwrapped, err := req.Unwrap()
+	if err != nil {
+		t.Fatalf("Unwrap() error = %v", err)
+	}
+
+	if got := unwrapped.Header.Get("Range"); got != "" {
+		t.Fatalf("req Range = %q, want it untouched", got)
+	}
+}
+
+func TestDownload_ResumesPartialFile(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer(t, serveArtifact(artifact, `"v1"`))
+	path := filepath.Join(t.TempDir(), "artifact.bin")
+
+	if err := os.WriteFile(path+partSuffix, artifact[:1000], 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(path+partSuffix+validatorSuffix, []byte(`"v1"`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Download(DefaultClient(server.URL).GET("/"), path, DownloadConfig{}); err != nil {
+		t.Fatalf("Download() error = %v", err)
+	}
+
+	assertFile(t, path, artifact)
+
+	if got := server.Header("Range"); len(got) != 1 || got[0] != "bytes=1000-" {
+		t.Fatalf("Range headers = %q, want bytes=1000-", got)
+	}
+}
+
+func TestDownload_RestartsChangedResource(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer(t, serveArtifact(artifact, `"v2"`))
+	path := filepath.Join(t.TempDir(), "artifact.bin")
+
+	if err := os.WriteFile(path+partSuffix, []byte("stale content"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(path+partSuffix+validatorSuffix, []byte(`"v1"`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Download(DefaultClient(server.URL).GET("/"), path, DownloadConfig{}); err != nil {
+		t.Fatalf("Download() error = %v", err)
+	}
+
+	assertFile(t, path, artifact)
+}
+
+func TestDownload_ChecksumMismatch(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer(t, serveArtifact(artifact, `"v1"`))
+	path := filepath.Join(t.TempDir(), "artifact.bin")
+
+	err := Download(DefaultClient(server.URL).GET("/"), path, DownloadConfig{
+		Checksums: []digest.Checksum{sha256Checksum(t, []byte("other"))},
+	})
+	if !errors.Is(err, digest.ErrChecksumMismatch) {
+		t.Fatalf("Download() error = %v, want %v", err, digest.ErrChecksumMismatch)
+	}
+
+	if _, err := os.Stat(path + partSuffix); !os.IsNotExist(err) {
+		t.Fatalf("partial file kept after a mismatch: %v", err)
+	}
+}
+
+func TestDownload_UnexpectedStatus(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.NotFoundHandler())
+	t.Cleanup(server.Close)
+
+	path := filepath.Join(t.TempDir(), "artifact.bin")
+
+	err := Download(DefaultClient(server.URL).GET("/"), path, DownloadConfig{})
+	if !errors.Is(err, ErrDownloadStatus) {
+		t.Fatalf("Download() error = %v, want %v", err, ErrDownloadStatus)
+	}
+
+	if entries, _ := os.ReadDir(filepath.Dir(path)); len(entries) != 0 {
+		t.Fatalf("files left behind: %v", entries)
+	}
+}
+
+func TestDownload_ExpectSuccess(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer(t, serveArtifact(artifact, `"v1"`))
+	path := filepath.Join(t.TempDir(), "artifact.bin")
+
+	// the partial file already holds the whole resource, answered with 416
+	if err := os.WriteFile(path+partSuffix, artifact, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(path+partSuffix+validatorSuffix, []byte(`"v1"`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	client := NewClient(server.URL).ExpectSuccess().Build()
+
+	if err := Download(client.GET("/"), path, DownloadConfig{}); err != nil {
+		t.Fatalf("Download() error = %v", err)
+	}
+
+	assertFile(t, path, artifact)
+
+	if got := server.Header("Range"); len(got) != 1 {
+		t.Fatalf("requests = %q, want a single one", got)
+	}
+
+	missing := httptest.NewServer(http.NotFoundHandler())
+	t.Cleanup(missing.Close)
+
+	err := Download(NewClient(missing.URL).ExpectSuccess().Build().GET("/"), path+".missing", DownloadConfig{MaxResumes: 3})
+	if !errors.Is(err, ErrDownloadStatus) {
+		t.Fatalf("Download() error = %v, want %v", err, ErrDownloadStatus)
+	}
+}
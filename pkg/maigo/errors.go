@@ -3,15 +3,37 @@ package maigo
 import "errors"
 
 var (
-	ErrEmptyBaseURL      = errors.New("empty base URL is not allowed")
-	ErrParseURL          = errors.New("failed to parse URL")
-	ErrClientValidation  = errors.New("invalid client attributes")
-	ErrRequestValidation = errors.New("invalid request attributes")
-	ErrCreateRequest     = errors.New("failed to create request")
-	ErrParseProxyURL     = errors.New("failed to parse proxy url")
-	ErrToSetBody         = errors.New("failed to set body")
-	ErrToMarshalJSON     = errors.New("failed to marshal json")
-	ErrToMarshalXML      = errors.New("failed to marshal xml")
+	ErrEmptyBaseURL         = errors.New("empty base URL is not allowed")
+	ErrParseURL             = errors.New("failed to parse URL")
+	ErrClientValidation     = errors.New("invalid client attributes")
+	ErrRequestValidation    = errors.New("invalid request attributes")
+	ErrCreateRequest        = errors.New("failed to create request")
+	ErrParseProxyURL        = errors.New("failed to parse proxy url")
+	ErrToSetBody            = errors.New("failed to set body")
+	ErrToMarshalJSON        = errors.New("failed to marshal json")
+	ErrToMarshalXML         = errors.New("failed to marshal xml")
+	ErrToMarshalForm        = errors.New("failed to marshal form")
+	ErrBodyNotReplayable    = errors.New("request body cannot be replayed")
+	ErrMediaTypeMismatch    = errors.New("content type does not match the body")
+	ErrUnsupportedMediaType = errors.New("no codec registered for media type")
+	ErrInvalidCodec         = errors.New("invalid codec")
+	ErrToEncodeBody         = errors.New("failed to encode body")
+	ErrToDecodeBody         = errors.New("failed to decode body")
+	ErrInvalidAuth          = errors.New("invalid authentication")
+	ErrAuthenticate         = errors.New("failed to authenticate request")
+	ErrTokenRequest         = errors.New("failed to obtain token")
+	ErrEmptyToken           = errors.New("token source returned an empty token")
+	ErrInvalidTimeout       = errors.New("invalid timeout")
+	ErrInvalidInterceptor   = errors.New("invalid interceptor")
+	ErrMissingPathParam     = errors.New("missing path parameter")
+	ErrUnknownPathParam     = errors.New("unknown path parameter")
+	ErrToWriteFile          = errors.New("failed to write file")
+	ErrDownloadStatus       = errors.New("unexpected download status")
+	ErrUnexpectedStatus     = errors.New("unexpected response status")
+	ErrNotProblem           = errors.New("response is not a problem details document")
+	ErrCookieJar            = errors.New("cannot use a cookie jar")
+	ErrInvalidCookie        = errors.New("invalid cookie")
+	ErrInvalidBalancer      = errors.New("invalid load balancer")
 
 	ErrAddingRawQueryToActualQuery = errors.New("cannot merge raw query into current query")
 	ErrSettingRawQuery             = errors.New("cannot parse raw query string")
@@ -0,0 +1,242 @@
+package maigo
+
+import (
+	"encoding"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+const formTag = "form"
+
+// encodeForm encodes v, a struct or a map with string keys, into form values.
+//
+// Struct fields are named after their `form:"name,omitempty"` tag, or after
+// the field itself when untagged, and skipped when tagged "-". Nested structs
+// and maps use bracket keys such as "address[city]", slices of scalars repeat
+// the key and slices of structs are indexed, as in "items[0][name]". Values
+// implementing encoding.TextMarshaler, time.Time included, are encoded as
+// text. Nil pointers and interfaces are skipped.
+func encodeForm(v any) (url.Values, error) {
+	values := url.Values{}
+
+	rv := indirect(reflect.ValueOf(v))
+	if !rv.IsValid() {
+		return values, nil
+	}
+
+	switch rv.Kind() {
+	case reflect.Struct, reflect.Map:
+		if err := encodeFormValue(values, "", rv); err != nil {
+			return nil, err
+		}
+
+		return values, nil
+	default:
+		return nil, fmt.Errorf("cannot encode %s as form, want struct or map", rv.Type())
+	}
+}
+
+func encodeFormValue(values url.Values, key string, rv reflect.Value) error {
+	rv = indirect(rv)
+	if !rv.IsValid() {
+		return nil
+	}
+
+	if marshaler, ok := textMarshaler(rv); ok {
+		text, err := marshaler.MarshalText()
+		if err != nil {
+			return fmt.Errorf("form field %q: %w", key, err)
+		}
+
+		values.Add(key, string(text))
+
+		return nil
+	}
+
+	switch rv.Kind() {
+	case reflect.Struct:
+		return encodeFormStruct(values, key, rv)
+	case reflect.Map:
+		return encodeFormMap(values, key, rv)
+	case reflect.Slice, reflect.Array:
+		if rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() == reflect.Uint8 {
+			values.Add(key, string(rv.Bytes()))
+			return nil
+		}
+
+		return encodeFormSlice(values, key, rv)
+	default:
+		text, err := formScalar(rv)
+		if err != nil {
+			return fmt.Errorf("form field %q: %w", key, err)
+		}
+
+		values.Add(key, text)
+
+		return nil
+	}
+}
+
+func encodeFormStruct(values url.Values, key string, rv reflect.Value) error {
+	rt := rv.Type()
+
+	for i := range rt.NumField() {
+		field := rt.Field(i)
+		if !field.IsExported() && !field.Anonymous {
+			continue
+		}
+
+		name, omitEmpty, skip := parseFormTag(field)
+		if skip {
+			continue
+		}
+
+		value := rv.Field(i)
+
+		if omitEmpty && value.IsZero() {
+			continue
+		}
+
+		// untagged embedded structs are flattened into the parent
+		if field.Anonymous && field.Tag.Get(formTag) == "" {
+			if embedded := indirect(value); embedded.Kind() == reflect.Struct {
+				if err := encodeFormStruct(values, key, embedded); err != nil {
+					return err
+				}
+
+				continue
+			}
+
+			if !field.IsExported() {
+				continue
+			}
+		}
+
+		if err := encodeFormValue(values, formKey(key, name), value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func encodeFormMap(values url.Values, key string, rv reflect.Value) error {
+	if rv.Type().Key().Kind() != reflect.String {
+		return fmt.Errorf("form field %q: map keys must be strings, got %s", key, rv.Type().Key())
+	}
+
+	iter := rv.MapRange()
+	for iter.Next() {
+		if err := encodeFormValue(values, formKey(key, iter.Key().String()), iter.Value()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func encodeFormSlice(values url.Values, key string, rv reflect.Value) error {
+	for i := range rv.Len() {
+		elem := rv.Index(i)
+
+		// scalars repeat the key, composite values need an index to keep
+		// their fields together
+		elemKey := key
+		if value := indirect(elem); value.Kind() == reflect.Struct || value.Kind() == reflect.Map {
+			if _, ok := textMarshaler(value); !ok {
+				elemKey = key + "[" + strconv.Itoa(i) + "]"
+			}
+		}
+
+		if err := encodeFormValue(values, elemKey, elem); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func formScalar(rv reflect.Value) (string, error) {
+	switch rv.Kind() {
+	case reflect.String:
+		return rv.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(rv.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(rv.Uint(), 10), nil
+	case reflect.Float32:
+		return strconv.FormatFloat(rv.Float(), 'f', -1, 32), nil
+	case reflect.Float64:
+		return strconv.FormatFloat(rv.Float(), 'f', -1, 64), nil
+	default:
+		return "", fmt.Errorf("unsupported type %s", rv.Type())
+	}
+}
+
+// parseFormTag returns the form name of field and whether it is omitted when
+// empty or skipped entirely.
+func parseFormTag(field reflect.StructField) (name string, omitEmpty, skip bool) {
+	tag := field.Tag.Get(formTag)
+	if tag == "-" {
+		return "", false, true
+	}
+
+	name, opts, _ := strings.Cut(tag, ",")
+	if name == "" {
+		name = field.Name
+	}
+
+	for opt := range strings.SplitSeq(opts, ",") {
+		if opt == "omitempty" {
+			omitEmpty = true
+		}
+	}
+
+	return name, omitEmpty, false
+}
+
+func formKey(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+
+	return prefix + "[" + name + "]"
+}
+
+// textMarshaler returns rv as an encoding.TextMarshaler, also considering
+// the methods with pointer receivers of addressable values.
+func textMarshaler(rv reflect.Value) (encoding.TextMarshaler, bool) {
+	if !rv.CanInterface() {
+		return nil, false
+	}
+
+	if marshaler, ok := rv.Interface().(encoding.TextMarshaler); ok {
+		return marshaler, true
+	}
+
+	if rv.CanAddr() {
+		marshaler, ok := rv.Addr().Interface().(encoding.TextMarshaler)
+		return marshaler, ok
+	}
+
+	return nil, false
+}
+
+// indirect dereferences pointers and interfaces, returning the zero Value
+// when a nil one is found.
+func indirect(rv reflect.Value) reflect.Value {
+	for rv.Kind() == reflect.Pointer || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return reflect.Value{}
+		}
+
+		rv = rv.Elem()
+	}
+
+	return rv
+}
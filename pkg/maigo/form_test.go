@@ -0,0 +1,138 @@
+package maigo
+
+import (
+	"errors"
+	"io"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/jeanmolossi/maigo/pkg/maigo/header"
+	"github.com/jeanmolossi/maigo/pkg/maigo/mime"
+)
+
+type formAddress struct {
+	City string `form:"city"`
+	Zip  string `form:"zip,omitempty"`
+}
+
+type formItem struct {
+	Name string `form:"name"`
+	Qty  int    `form:"qty"`
+}
+
+type formMeta struct {
+	Source string `form:"source"`
+}
+
+type formOrder struct {
+	formMeta
+
+	ID       int64        `form:"id"`
+	Note     string       `form:"note,omitempty"`
+	Tags     []string     `form:"tags"`
+	Items    []formItem   `form:"items"`
+	Address  *formAddress `form:"address"`
+	Billing  *formAddress `form:"billing"`
+	Labels   map[string]string
+	PlacedAt time.Time `form:"placed_at"`
+	Secret   string    `form:"-"`
+	internal string
+}
+
+func TestEncodeForm(t *testing.T) {
+	t.Parallel()
+
+	values, err := encodeForm(&formOrder{
+		formMeta: formMeta{Source: "web"},
+		ID:       7,
+		Tags:     []string{"a", "b"},
+		Items:    []formItem{{Name: "mug", Qty: 2}},
+		Address:  &formAddress{City: "Fujisawa"},
+		Labels:   map[string]string{"gift": "yes"},
+		PlacedAt: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+		Secret:   "hidden",
+		internal: "hidden",
+	})
+	if err != nil {
+		t.Fatalf("encodeForm() error = %v", err)
+	}
+
+	want := url.Values{
+		"source":         {"web"},
+		"id":             {"7"},
+		"tags":           {"a", "b"},
+		"items[0][name]": {"mug"},
+		"items[0][qty]":  {"2"},
+		"address[city]":  {"Fujisawa"},
+		"Labels[gift]":   {"yes"},
+		"placed_at":      {"2024-05-01T10:00:00Z"},
+	}
+
+	if got, want := values.Encode(), want.Encode(); got != want {
+		t.Fatalf("encodeForm() = %s, want %s", got, want)
+	}
+}
+
+func TestEncodeForm_Unsupported(t *testing.T) {
+	t.Parallel()
+
+	if _, err := encodeForm([]string{"a"}); err == nil {
+		t.Fatal("encodeForm(slice) error = nil, want error")
+	}
+
+	if _, err := encodeForm(struct{ C chan int }{C: make(chan int)}); err == nil {
+		t.Fatal("encodeForm(chan field) error = nil, want error")
+	}
+}
+
+func TestRequestBody_AsForm(t *testing.T) {
+	t.Parallel()
+
+	client := DefaultClient("https://example.com")
+
+	t.Run("sets content type", func(t *testing.T) {
+		t.Parallel()
+
+		req, err := client.POST("/token").
+			Body().AsForm(url.Values{"grant_type": {"client_credentials"}}).
+			Unwrap()
+		if err != nil {
+			t.Fatalf("Unwrap() error = %v", err)
+		}
+
+		if got := req.Header.Get(header.ContentType.String()); got != string(mime.FormURLEncoded) {
+			t.Fatalf("Content-Type = %q, want %q", got, mime.FormURLEncoded)
+		}
+
+		body, _ := io.ReadAll(req.Body)
+		if string(body) != "grant_type=client_credentials" {
+			t.Fatalf("body = %q, want %q", body, "grant_type=client_credentials")
+		}
+	})
+
+	t.Run("keeps explicit content type", func(t *testing.T) {
+		t.Parallel()
+
+		req, err := client.POST("/token").
+			Header().Set(header.ContentType, "application/x-www-form-urlencoded; charset=utf-8").
+			Body().AsFormStruct(formAddress{City: "Tokyo"}).
+			Unwrap()
+		if err != nil {
+			t.Fatalf("Unwrap() error = %v", err)
+		}
+
+		if got := req.Header.Get(header.ContentType.String()); got != "application/x-www-form-urlencoded; charset=utf-8" {
+			t.Fatalf("Content-Type = %q", got)
+		}
+	})
+
+	t.Run("invalid struct", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := client.POST("/token").Body().AsFormStruct(42).Unwrap()
+		if !errors.Is(err, ErrToMarshalForm) {
+			t.Fatalf("Unwrap() error = %v, want %v", err, ErrToMarshalForm)
+		}
+	})
+}
@@ -1,7 +1,9 @@
 package maigo
 
 import (
+	"maps"
 	"net/http"
+	"slices"
 	"sync"
 
 	"github.com/jeanmolossi/maigo/pkg/maigo/contracts"
@@ -9,20 +11,50 @@ import (
 	"golang.org/x/net/http/httpguts"
 )
 
-var _ contracts.Header = (*Header)(nil)
+var (
+	_ contracts.Header       = (*Header)(nil)
+	_ contracts.HeaderEditor = (*Header)(nil)
+)
+
+// mergePolicy tells how a request header is merged with the client ones.
+type mergePolicy uint8
+
+const (
+	// mergeOverride replaces the client values.
+	mergeOverride mergePolicy = iota
+	// mergeAppend adds the values after the client ones.
+	mergeAppend
+	// mergeRemove drops the client values.
+	mergeRemove
+)
 
 // Header wraps an http.Header map providing concurrency-safe access and
 // validation of header names and values according to RFC 9110.
 // A nil Header behaves like an empty map; all methods are no-ops on a nil
 // receiver.
+//
+// Header also records how each field was last changed, so request headers
+// can be merged with the client ones: values given to Add and Set replace
+// the client values, values given to Append follow them, and Del drops them.
 type Header struct {
-	mu  sync.RWMutex
-	hdr http.Header
+	mu       sync.RWMutex
+	hdr      http.Header
+	policies map[string]mergePolicy
 }
 
 // Add appends value to the field named by key. It creates the map on
 // first use and silently discards invalid names or values.
 func (h *Header) Add(key header.Type, value string) {
+	h.add(key, value, mergeOverride)
+}
+
+// Append is like Add, but merged with the client headers the values follow
+// the client ones instead of replacing them.
+func (h *Header) Append(key header.Type, value string) {
+	h.add(key, value, mergeAppend)
+}
+
+func (h *Header) add(key header.Type, value string, policy mergePolicy) {
 	if h == nil {
 		return
 	}
@@ -41,6 +73,13 @@ func (h *Header) Add(key header.Type, value string) {
 	}
 
 	h.hdr.Add(ks, value)
+
+	// appending to removed values keeps the client ones away
+	if policy == mergeAppend && h.policy(ks) == mergeRemove {
+		policy = mergeOverride
+	}
+
+	h.setPolicy(ks, policy)
 }
 
 // Get retrieves the first value associated with key. It returns an
@@ -87,6 +126,96 @@ func (h *Header) Set(key header.Type, value string) {
 	}
 
 	h.hdr.Set(ks, value)
+	h.setPolicy(ks, mergeOverride)
+}
+
+// Del removes the values of key. Merged with the client headers, it drops
+// the client values as well. Invalid names are ignored.
+func (h *Header) Del(key header.Type) {
+	if h == nil {
+		return
+	}
+
+	ks := key.String()
+	if !httpguts.ValidHeaderFieldName(ks) {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.hdr.Del(ks)
+	h.setPolicy(ks, mergeRemove)
+}
+
+// appendHeader appends value to key of h, the same as Add when h does not
+// implement contracts.HeaderEditor.
+func appendHeader(h contracts.Header, key header.Type, value string) {
+	if editor, ok := h.(contracts.HeaderEditor); ok {
+		editor.Append(key, value)
+		return
+	}
+
+	h.Add(key, value)
+}
+
+// removeHeader removes key from h, when h implements contracts.HeaderEditor.
+func removeHeader(h contracts.Header, key header.Type) {
+	if editor, ok := h.(contracts.HeaderEditor); ok {
+		editor.Del(key)
+	}
+}
+
+// mergeInto applies the header to dst according to how each field was
+// changed: appended values follow the ones in dst, other values replace them
+// and deleted fields are removed from it.
+func (h *Header) mergeInto(dst http.Header) {
+	if h == nil {
+		return
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for key, policy := range h.policies {
+		if policy == mergeRemove {
+			dst.Del(key)
+		}
+	}
+
+	for key, values := range h.hdr {
+		if h.policy(key) == mergeAppend {
+			dst[key] = append(dst[key], values...)
+		} else {
+			dst[key] = slices.Clone(values)
+		}
+	}
+}
+
+// clone returns a copy of the header, merge policies included.
+func (h *Header) clone() *Header {
+	if h == nil {
+		return newDefaultHTTPHeader()
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return &Header{hdr: h.hdr.Clone(), policies: maps.Clone(h.policies)}
+}
+
+// policy must be called with h.mu held.
+func (h *Header) policy(key string) mergePolicy {
+	return h.policies[http.CanonicalHeaderKey(key)]
+}
+
+// setPolicy must be called with h.mu held.
+func (h *Header) setPolicy(key string, policy mergePolicy) {
+	if h.policies == nil {
+		h.policies = map[string]mergePolicy{}
+	}
+
+	h.policies[http.CanonicalHeaderKey(key)] = policy
 }
 
 // Unwrap returns a copy of the underlying header map. The caller may
@@ -111,6 +240,28 @@ func (h *Header) Unwrap() *http.Header {
 	return &cloned
 }
 
+// cloneHeader copies h, keeping the merge policies of a *Header.
+func cloneHeader(h contracts.Header) *Header {
+	if hdr, ok := h.(*Header); ok {
+		return hdr.clone()
+	}
+
+	return &Header{hdr: *h.Unwrap()}
+}
+
+// mergeHeader merges the request header src into dst. Headers other than
+// *Header replace the values in dst.
+func mergeHeader(dst http.Header, src contracts.Header) {
+	if hdr, ok := src.(*Header); ok {
+		hdr.mergeInto(dst)
+		return
+	}
+
+	for key, values := range *src.Unwrap() {
+		dst[key] = values
+	}
+}
+
 // newDefaultHTTPHeader initializes a new Header with an empty map.
 func newDefaultHTTPHeader() *Header {
 	return &Header{
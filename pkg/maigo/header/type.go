@@ -24,6 +24,7 @@ const (
 	Allow                         Type = "Allow"
 	Authorization                 Type = "Authorization"
 	CacheControl                  Type = "Cache-Control"
+	ContentDigest                 Type = "Content-Digest"
 	ContentDisposition            Type = "Content-Disposition"
 	ContentEncoding               Type = "Content-Encoding"
 	ContentLanguage               Type = "Content-Language"
@@ -41,6 +42,7 @@ const (
 	IfNoneMatch                   Type = "If-None-Match"
 	IfRange                       Type = "If-Range"
 	IfUnmodifiedSince             Type = "If-Unmodified-Since"
+	LastEventID                   Type = "Last-Event-ID"
 	LastModified                  Type = "Last-Modified"
 	Link                          Type = "Link"
 	Location                      Type = "Location"
@@ -53,6 +55,7 @@ const (
 	Range                         Type = "Range"
 	Referer                       Type = "Referer"
 	Refresh                       Type = "Refresh"
+	ReprDigest                    Type = "Repr-Digest"
 	RetryAfter                    Type = "Retry-After"
 	Server                        Type = "Server"
 	SetCookie                     Type = "Set-Cookie"
@@ -1,9 +1,12 @@
 package maigo
 
 import (
+	"net/http"
+	"reflect"
 	"sync"
 	"testing"
 
+	"github.com/jeanmolossi/maigo/pkg/maigo/contracts"
 	"github.com/jeanmolossi/maigo/pkg/maigo/header"
 )
 
@@ -184,6 +187,79 @@ func TestHeader_InvalidInputIgnored(t *testing.T) {
 	}
 }
 
+func TestHeader_MergeInto(t *testing.T) {
+	t.Parallel()
+
+	h := newDefaultHTTPHeader()
+	h.Append("X-Append", "request")
+	h.Add("X-Override", "request")
+	h.Del("X-Remove")
+	h.Del("X-Readd")
+	h.Append("X-Readd", "request")
+
+	dst := http.Header{
+		"X-Append":   {"client"},
+		"X-Override": {"client-1", "client-2"},
+		"X-Remove":   {"client"},
+		"X-Readd":    {"client"},
+		"X-Kept":     {"client"},
+	}
+
+	h.mergeInto(dst)
+
+	want := http.Header{
+		"X-Append":   {"client", "request"},
+		"X-Override": {"request"},
+		"X-Readd":    {"request"},
+		"X-Kept":     {"client"},
+	}
+
+	if !reflect.DeepEqual(dst, want) {
+		t.Fatalf("mergeInto() = %v, want %v", dst, want)
+	}
+}
+
+func TestHeader_MergeWithClient(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer(t, echo)
+
+	client := NewClient(server.URL).
+		Header().Add("X-Tag", "client-1").
+		Header().Add("X-Tag", "client-2").
+		Build()
+
+	tests := []struct {
+		name string
+		req  contracts.RequestBuilder
+		want string
+	}{
+		{name: "multi value", req: client.GET("/"), want: "client-1,client-2"},
+		{name: "add", req: client.GET("/").Header().Add("X-Tag", "request"), want: "request"},
+		{name: "set", req: client.GET("/").Header().Set("X-Tag", "request"), want: "request"},
+		{name: "append", req: client.GET("/").Header().Append("X-Tag", "request"), want: "client-1,client-2,request"},
+		{name: "remove", req: client.GET("/").Header().Remove("X-Tag"), want: ""},
+		{
+			name: "remove then add",
+			req:  client.GET("/").Header().Remove("X-Tag").Header().Append("X-Tag", "request"),
+			want: "request",
+		},
+		{
+			name: "clone keeps policy",
+			req:  client.GET("/").Header().Append("X-Tag", "request").Clone(),
+			want: "client-1,client-2,request",
+		},
+	}
+
+	for _, tt := range tests {
+		header, _ := sendAndRead(t, tt.req)
+
+		if got := header.Get("X-Tags"); got != tt.want {
+			t.Errorf("%s: X-Tag = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
 func BenchmarkHeaderAdd(b *testing.B) {
 	b.ReportAllocs()
 
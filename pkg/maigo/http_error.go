@@ -0,0 +1,207 @@
+package maigo
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/jeanmolossi/maigo/pkg/maigo/contracts"
+	"github.com/jeanmolossi/maigo/pkg/maigo/header"
+)
+
+const (
+	// maxErrorSnippet bounds the body kept by an HTTPError.
+	maxErrorSnippet = 4 << 10 // 4KiB
+	// maxErrorBody bounds the error payload read to be decoded.
+	maxErrorBody = 1 << 20 // 1MiB
+
+	redacted = "xxxxx"
+)
+
+// sensitiveParams are the query parameter names, or parts of them, whose
+// values are redacted from HTTPError URLs.
+var sensitiveParams = []string{"token", "key", "secret", "password", "passwd", "signature", "auth", "session", "credential"}
+
+// HTTPError reports a response whose status is not 2xx, carrying its error
+// payload decoded into E.
+//
+// Requests sent with ExpectSuccess report non-2xx responses as a
+// *HTTPError[[]byte] whose Body is the Snippet:
+//
+//	var httpErr *maigo.HTTPError[[]byte]
+//	if errors.As(err, &httpErr) && httpErr.StatusCode == http.StatusNotFound {
+//	    ...
+//	}
+type HTTPError[E any] struct {
+	// StatusCode is the response status code, such as 404.
+	StatusCode int
+	// Status is the response status text, such as "Not Found".
+	Status string
+	// Method is the method of the request.
+	Method string
+	// URL is the request URL, its password and the values of query
+	// parameters such as token or api_key redacted.
+	URL string
+	// Header holds the response headers, the Set-Cookie values redacted.
+	Header http.Header
+	// Snippet holds the first bytes of the response body, at most 4KiB.
+	Snippet []byte
+	// Attempts is the number of attempts made, retries included.
+	Attempts int
+	// Body is the error payload decoded with the codec of the response
+	// Content-Type, or the zero value when it is empty or cannot be decoded.
+	Body E
+	// Problem is the decoded application/problem+json body, if any.
+	Problem *Problem
+}
+
+// Error implements error.
+func (e *HTTPError[E]) Error() string {
+	if e.Method == "" {
+		return fmt.Sprintf("%s: %d %s", ErrUnexpectedStatus, e.StatusCode, e.Status)
+	}
+
+	return fmt.Sprintf("%s: %s %s: %d %s", ErrUnexpectedStatus, e.Method, e.URL, e.StatusCode, e.Status)
+}
+
+// Unwrap returns ErrUnexpectedStatus, so errors.Is matches any HTTPError
+// regardless of its payload type, and the Problem, so errors.As finds it.
+func (e *HTTPError[E]) Unwrap() []error {
+	if e.Problem != nil {
+		return []error{ErrUnexpectedStatus, e.Problem}
+	}
+
+	return []error{ErrUnexpectedStatus}
+}
+
+// newHTTPError describes resp, whose body starts with data.
+func newHTTPError[E any](resp contracts.Response, data []byte) *HTTPError[E] {
+	raw := resp.Raw()
+
+	httpErr := &HTTPError[E]{
+		StatusCode: raw.StatusCode,
+		Status:     http.StatusText(raw.StatusCode),
+		Header:     redactHeader(raw.Header),
+		Snippet:    data[:min(len(data), maxErrorSnippet)],
+		Attempts:   1,
+	}
+
+	if raw.Request != nil {
+		httpErr.Method = raw.Request.Method
+		httpErr.URL = redactURL(raw.Request.URL)
+	}
+
+	if response, ok := resp.(*Response); ok && response.attempts > 0 {
+		httpErr.Attempts = response.attempts
+	}
+
+	if resp.Body().IsProblem() {
+		problem := &Problem{}
+		if err := json.Unmarshal(data, problem); err == nil {
+			httpErr.Problem = problem
+		}
+	}
+
+	return httpErr
+}
+
+// statusError closes resp, reporting it as an HTTPError holding the start
+// of its body and its problem document.
+func statusError(resp contracts.Response, attempts int) *HTTPError[[]byte] {
+	limit := int64(maxErrorSnippet)
+	// problem documents are decoded whole
+	if resp.Body().IsProblem() {
+		limit = maxErrorBody
+	}
+
+	data := readErrorBody(resp, limit)
+
+	httpErr := newHTTPError[[]byte](resp, data)
+	httpErr.Body = httpErr.Snippet
+
+	if attempts > 0 {
+		httpErr.Attempts = attempts
+	}
+
+	return httpErr
+}
+
+// readErrorBody reads up to limit bytes of the body of resp and closes it.
+// A body that failed midway keeps what was read.
+func readErrorBody(resp contracts.Response, limit int64) []byte {
+	defer resp.Body().Close()
+
+	reader := responseReader(resp)
+	data, _ := io.ReadAll(io.LimitReader(reader, limit))
+
+	// drain to allow the connection to be reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(reader, 1<<20))
+
+	return data
+}
+
+// redactURL formats u without its password and the values of sensitive
+// query parameters.
+func redactURL(u *url.URL) string {
+	if u == nil {
+		return ""
+	}
+
+	clean := *u
+
+	if query := clean.Query(); len(query) > 0 {
+		changed := false
+
+		for key, values := range query {
+			if !isSensitiveParam(key) {
+				continue
+			}
+
+			for i := range values {
+				values[i] = redacted
+			}
+
+			changed = true
+		}
+
+		if changed {
+			clean.RawQuery = query.Encode()
+		}
+	}
+
+	return clean.Redacted()
+}
+
+func isSensitiveParam(key string) bool {
+	key = strings.ToLower(key)
+
+	for _, sensitive := range sensitiveParams {
+		if strings.Contains(key, sensitive) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// redactHeader copies h, replacing the values of cookies and credentials.
+func redactHeader(h http.Header) http.Header {
+	clean := h.Clone()
+
+	for _, key := range []header.Type{header.Authorization, header.ProxyAuthorization} {
+		if values := clean.Values(key.String()); len(values) > 0 {
+			clean[http.CanonicalHeaderKey(key.String())] = []string{redacted}
+		}
+	}
+
+	cookies := clean.Values(header.SetCookie.String())
+	for i, cookie := range cookies {
+		name, _, _ := strings.Cut(cookie, "=")
+		cookies[i] = name + "=" + redacted
+	}
+
+	return clean
+}
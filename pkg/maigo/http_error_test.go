@@ -0,0 +1,175 @@
+package maigo
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jeanmolossi/maigo/pkg/maigo/contracts"
+)
+
+// statusResponse answers with status, a session cookie and a body of
+// bodySize bytes.
+func statusResponse(status, bodySize int) http.HandlerFunc {
+	return respond(status, strings.Repeat("e", bodySize),
+		"Content-Type", "text/plain",
+		"Set-Cookie", "session=secret; HttpOnly",
+	)
+}
+
+func TestExpectSuccess_Request(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer(t, statusResponse(http.StatusInternalServerError, 10<<10))
+	target := strings.Replace(server.URL, "http://", "http://user:pass@", 1)
+
+	_, err := DefaultClient(target).
+		GET("/orders").
+		Query().AddParam("access_token", "abc").
+		Query().AddParam("page", "2").
+		ExpectSuccess().
+		Send()
+
+	var httpErr *HTTPError[[]byte]
+	if !errors.As(err, &httpErr) {
+		t.Fatalf("Send() error = %v, want *HTTPError[[]byte]", err)
+	}
+
+	if !errors.Is(err, ErrUnexpectedStatus) {
+		t.Fatalf("errors.Is(err, ErrUnexpectedStatus) = false")
+	}
+
+	if httpErr.StatusCode != http.StatusInternalServerError || httpErr.Method != http.MethodGet || httpErr.Attempts != 1 {
+		t.Fatalf("httpErr = %d %s, %d attempts", httpErr.StatusCode, httpErr.Method, httpErr.Attempts)
+	}
+
+	for _, leak := range []string{"abc", "pass"} {
+		if strings.Contains(httpErr.URL, leak) || strings.Contains(err.Error(), leak) {
+			t.Fatalf("URL %q leaks %q", httpErr.URL, leak)
+		}
+	}
+
+	if !strings.Contains(httpErr.URL, "access_token=xxxxx") || !strings.Contains(httpErr.URL, "page=2") {
+		t.Fatalf("URL = %q, want the token redacted and the page kept", httpErr.URL)
+	}
+
+	if got := httpErr.Header.Get("Set-Cookie"); got != "session=xxxxx" {
+		t.Fatalf("Set-Cookie = %q, want session=xxxxx", got)
+	}
+
+	if len(httpErr.Snippet) != maxErrorSnippet || string(httpErr.Body) != string(httpErr.Snippet) {
+		t.Fatalf("snippet has %d bytes, want %d", len(httpErr.Snippet), maxErrorSnippet)
+	}
+}
+
+func TestExpectSuccess_Client(t *testing.T) {
+	t.Parallel()
+
+	failing := newTestServer(t, statusResponse(http.StatusNotFound, 10))
+
+	_, err := NewClient(failing.URL).ExpectSuccess().Build().GET("/").Send()
+	if !errors.Is(err, ErrUnexpectedStatus) {
+		t.Fatalf("Send() error = %v, want %v", err, ErrUnexpectedStatus)
+	}
+
+	ok := newTestServer(t, statusResponse(http.StatusOK, 10))
+
+	resp, err := NewClient(ok.URL).ExpectSuccess().Build().GET("/").Send()
+	if err != nil || !resp.Status().IsOK() {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	resp.Body().Close()
+
+	// without ExpectSuccess the response is returned as is
+	resp, err = DefaultClient(failing.URL).GET("/").Send()
+	if err != nil || !resp.Status().IsNotFound() {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	resp.Body().Close()
+}
+
+func TestExpectSuccess_RetriesExhausted(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer(t, inTurn(statusResponse(http.StatusBadGateway, 10), statusResponse(http.StatusServiceUnavailable, 10)))
+
+	_, err := DefaultClient(server.URL).
+		GET("/").
+		Retry().SetConstantBackoff(time.Millisecond, 3).
+		Send()
+	if err == nil || !strings.HasPrefix(err.Error(), "request failed after 3 attempts") {
+		t.Fatalf("Send() error = %v, want the attempts summary", err)
+	}
+
+	var httpErr *HTTPError[[]byte]
+	if !errors.As(err, &httpErr) {
+		t.Fatalf("Send() error = %v, want *HTTPError[[]byte]", err)
+	}
+
+	if httpErr.Attempts != 3 || httpErr.StatusCode != http.StatusServiceUnavailable || string(httpErr.Snippet) != "eeeeeeeeee" {
+		t.Fatalf("httpErr = %d after %d attempts, snippet %q, want the last attempt", httpErr.StatusCode, httpErr.Attempts, httpErr.Snippet)
+	}
+}
+
+func TestExpectSuccess_RetriedSuccess(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer(t, inTurn(statusResponse(http.StatusServiceUnavailable, 10), statusResponse(http.StatusOK, 10)))
+
+	resp, err := DefaultClient(server.URL).
+		GET("/").
+		Retry().SetConstantBackoff(time.Millisecond, 3).
+		ExpectSuccess().
+		Send()
+	if err != nil || !resp.Status().IsOK() {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	resp.Body().Close()
+}
+
+func TestDo_IgnoresExpectSuccess(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer(t, statusResponse(http.StatusConflict, 10))
+
+	_, apiErr, err := Do[string, string](NewClient(server.URL).ExpectSuccess().Build().GET("/"))
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+
+	if apiErr == nil || apiErr.StatusCode != http.StatusConflict || apiErr.Body != "eeeeeeeeee" || apiErr.Method != http.MethodGet {
+		t.Fatalf("apiErr = %+v, want 409 with the body", apiErr)
+	}
+}
+
+func TestExpectSuccess_SetByInterceptor(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer(t, statusResponse(http.StatusNotFound, 10))
+
+	req := DefaultClient(server.URL).GET("/").
+		Intercept().OnRequest(func(req contracts.RequestBuilder) (contracts.Response, error) {
+		req.ExpectSuccess()
+		return nil, nil
+	})
+
+	if _, err := req.Send(); !errors.Is(err, ErrUnexpectedStatus) {
+		t.Fatalf("Send() error = %v, want ErrUnexpectedStatus", err)
+	}
+
+	resp, err := req.SendAnyStatus()
+	if err != nil {
+		t.Fatalf("SendAnyStatus() error = %v", err)
+	}
+
+	defer resp.Body().Close()
+
+	if resp.Status().Code() != http.StatusNotFound {
+		t.Fatalf("status = %d, want 404", resp.Status().Code())
+	}
+}
@@ -0,0 +1,130 @@
+package maigo
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/jeanmolossi/maigo/pkg/maigo/contracts"
+)
+
+func TestInterceptors_Order(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Tenant", r.Header.Get("X-Tenant"))
+	}))
+	t.Cleanup(server.Close)
+
+	var calls []string
+
+	onRequest := func(name string) contracts.RequestInterceptor {
+		return func(req contracts.RequestBuilder) (contracts.Response, error) {
+			calls = append(calls, "request:"+name)
+			req.Header().Set("X-Tenant", name)
+
+			return nil, nil
+		}
+	}
+
+	onResponse := func(name string) contracts.ResponseInterceptor {
+		return func(resp contracts.Response) (contracts.Response, error) {
+			calls = append(calls, "response:"+name)
+			return resp, nil
+		}
+	}
+
+	client := NewClient(server.URL).
+		Intercept().OnRequest(onRequest("client")).
+		Intercept().OnResponse(onResponse("client")).
+		Build()
+
+	resp, err := client.GET("/").
+		Intercept().OnRequest(onRequest("request")).
+		Intercept().OnResponse(onResponse("request")).
+		Send()
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	// the request interceptor ran last, so its header wins
+	if got := resp.Header().Get("X-Tenant"); got != "request" {
+		t.Fatalf("X-Tenant = %q, want %q", got, "request")
+	}
+
+	want := []string{"request:client", "request:request", "response:request", "response:client"}
+	if !slices.Equal(calls, want) {
+		t.Fatalf("calls = %v, want %v", calls, want)
+	}
+}
+
+func TestInterceptors_ShortCircuit(t *testing.T) {
+	t.Parallel()
+
+	var hits atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		hits.Add(1)
+	}))
+	t.Cleanup(server.Close)
+
+	resp, err := DefaultClient(server.URL).
+		GET("/").
+		Intercept().OnRequest(func(contracts.RequestBuilder) (contracts.Response, error) {
+		return NewResponse(&http.Response{
+			StatusCode: http.StatusTeapot,
+			Body:       io.NopCloser(strings.NewReader("cached")),
+		}), nil
+	}).
+		Send()
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	body, _ := resp.Body().AsString()
+	if resp.Status().Code() != http.StatusTeapot || body != "cached" {
+		t.Fatalf("got [%d] %q, want [418] %q", resp.Status().Code(), body, "cached")
+	}
+
+	if got := hits.Load(); got != 0 {
+		t.Fatalf("server hits = %d, want 0", got)
+	}
+}
+
+func TestInterceptors_ResponseToError(t *testing.T) {
+	t.Parallel()
+
+	errNotFound := errors.New("user not found")
+
+	server := httptest.NewServer(http.NotFoundHandler())
+	t.Cleanup(server.Close)
+
+	client := NewClient(server.URL).
+		Intercept().OnResponse(func(resp contracts.Response) (contracts.Response, error) {
+		if resp.Status().IsNotFound() {
+			return nil, errNotFound
+		}
+
+		return resp, nil
+	}).
+		Build()
+
+	_, err := client.GET("/users/1").Send()
+	if !errors.Is(err, errNotFound) {
+		t.Fatalf("Send() error = %v, want %v", err, errNotFound)
+	}
+}
+
+func TestInterceptors_Nil(t *testing.T) {
+	t.Parallel()
+
+	_, err := DefaultClient("https://example.com").GET("/").Intercept().OnResponse(nil).Send()
+	if !errors.Is(err, ErrInvalidInterceptor) {
+		t.Fatalf("Send() error = %v, want %v", err, ErrInvalidInterceptor)
+	}
+}
@@ -0,0 +1,34 @@
+package maigo
+
+import "github.com/jeanmolossi/maigo/pkg/maigo/contracts"
+
+var _ contracts.ConfigInterceptors = (*Interceptors)(nil)
+
+type Interceptors struct {
+	request  []contracts.RequestInterceptor
+	response []contracts.ResponseInterceptor
+}
+
+// AddRequestInterceptor implements contracts.ConfigInterceptors.
+func (i *Interceptors) AddRequestInterceptor(interceptor contracts.RequestInterceptor) {
+	i.request = append(i.request, interceptor)
+}
+
+// AddResponseInterceptor implements contracts.ConfigInterceptors.
+func (i *Interceptors) AddResponseInterceptor(interceptor contracts.ResponseInterceptor) {
+	i.response = append(i.response, interceptor)
+}
+
+// RequestInterceptors implements contracts.ConfigInterceptors.
+func (i *Interceptors) RequestInterceptors() []contracts.RequestInterceptor {
+	return i.request
+}
+
+// ResponseInterceptors implements contracts.ConfigInterceptors.
+func (i *Interceptors) ResponseInterceptors() []contracts.ResponseInterceptor {
+	return i.response
+}
+
+func newDefaultInterceptors() *Interceptors {
+	return &Interceptors{}
+}
@@ -0,0 +1,357 @@
+// Package jar provides the cookie jars used with Cookie().Jar: an in-memory
+// jar aware of the public suffix list, so a response cannot set cookies for
+// a whole registry such as co.uk, and a jar persisted to a file, so sessions
+// survive process restarts.
+package jar
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/publicsuffix"
+)
+
+// filePerm keeps the stored cookies, often credentials, private to the user.
+const filePerm = 0o600
+
+var (
+	ErrLoad = errors.New("failed to load cookie jar")
+	ErrSave = errors.New("failed to save cookie jar")
+)
+
+// Compile time check if [File] implements [http.CookieJar].
+var _ http.CookieJar = (*File)(nil)
+
+// New creates an in-memory jar using the public suffix list.
+func New() *cookiejar.Jar {
+	//nolint:errcheck // cookiejar.New never fails
+	jar, _ := cookiejar.New(&cookiejar.Options{PublicSuffixList: publicsuffix.List})
+
+	return jar
+}
+
+// saveDelay batches the saves of the cookies set by a burst of responses.
+const saveDelay = time.Second
+
+// File is a jar, as returned by New, whose cookies are saved to a JSON file
+// and loaded back by NewFile. The cookies a response sets are saved in the
+// background shortly after; Save writes them at once, so it should be
+// called before the process exits. Session cookies are saved as well. File
+// is safe for concurrent use.
+type File struct {
+	mu      sync.Mutex
+	jar     *cookiejar.Jar
+	path    string
+	records []record
+	// delay is how long after a change the file is saved.
+	delay time.Duration
+	// scheduled tells whether a background save is pending.
+	scheduled bool
+	// err is the error of the last save.
+	err error
+
+	// saveMu keeps the writes of the file in order.
+	saveMu sync.Mutex
+}
+
+// record is a cookie as received, with the URL it was received from, so the
+// jar can be rebuilt by setting it again.
+type record struct {
+	URL         string        `json:"url"`
+	Name        string        `json:"name"`
+	Value       string        `json:"value"`
+	Quoted      bool          `json:"quoted,omitempty"`
+	Domain      string        `json:"domain,omitempty"`
+	Path        string        `json:"path,omitempty"`
+	Expires     time.Time     `json:"expires,omitzero"`
+	Secure      bool          `json:"secure,omitempty"`
+	HttpOnly    bool          `json:"httpOnly,omitempty"`
+	SameSite    http.SameSite `json:"sameSite,omitempty"`
+	Partitioned bool          `json:"partitioned,omitempty"`
+
+	// id is the key of the cookie in the jar.
+	id string
+}
+
+// NewFile creates a jar persisted to path, loading the cookies saved there.
+// A missing file is created on the first save.
+//
+// Example:
+//
+//	cookies, err := jar.NewFile(filepath.Join(dir, "cookies.json"))
+//	if err != nil {
+//	    return err
+//	}
+//
+//	client := maigo.NewClient("https://example.com").
+//	        Cookie().Jar(cookies).
+//	        Build()
+func NewFile(path string) (*File, error) {
+	f := &File{jar: New(), path: path, delay: saveDelay}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return f, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrLoad, err)
+	}
+
+	var records []record
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrLoad, path, err)
+	}
+
+	now := time.Now()
+
+	for _, rec := range records {
+		u, err := url.Parse(rec.URL)
+		if err != nil || rec.expired(now) {
+			continue
+		}
+
+		f.jar.SetCookies(u, []*http.Cookie{rec.cookie()})
+
+		rec.id = rec.key(u)
+		f.records = append(f.records, rec)
+	}
+
+	return f, nil
+}
+
+// Path returns the file the jar is saved to.
+func (f *File) Path() string {
+	return f.path
+}
+
+// Cookies implements http.CookieJar.
+func (f *File) Cookies(u *url.URL) []*http.Cookie {
+	return f.jar.Cookies(u)
+}
+
+// SetCookies implements http.CookieJar, saving the cookies the jar accepted
+// in the background. A failing save is reported by Err.
+func (f *File) SetCookies(u *url.URL, cookies []*http.Cookie) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.jar.SetCookies(u, cookies)
+
+	now := time.Now()
+
+	for _, cookie := range cookies {
+		rec := newRecord(u, cookie, now)
+		expired := rec.expired(now)
+
+		// cookies the jar rejected are not stored either
+		if !expired && !f.accepted(u, cookie) {
+			continue
+		}
+
+		// a newer cookie with the same name, domain and path replaces the
+		// stored one, or deletes it when expired
+		f.records = slices.DeleteFunc(f.records, func(stored record) bool { return stored.id == rec.id })
+
+		if !expired {
+			f.records = append(f.records, rec)
+		}
+	}
+
+	if !f.scheduled {
+		f.scheduled = true
+		time.AfterFunc(f.delay, f.saveInBackground)
+	}
+}
+
+// Save writes the jar to its file.
+func (f *File) Save() error {
+	f.saveMu.Lock()
+	defer f.saveMu.Unlock()
+
+	f.mu.Lock()
+	data, err := f.marshalLocked()
+	f.mu.Unlock()
+
+	if err == nil {
+		err = writeFile(f.path, data)
+	}
+
+	f.mu.Lock()
+	f.err = err
+	f.mu.Unlock()
+
+	return err
+}
+
+// Err returns the error of the last save, or nil when it succeeded.
+func (f *File) Err() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.err
+}
+
+func (f *File) saveInBackground() {
+	f.mu.Lock()
+	f.scheduled = false
+	f.mu.Unlock()
+
+	// reported by Err
+	_ = f.Save()
+}
+
+// accepted reports whether the jar kept cookie, as set from u, by looking it
+// up where it is sent.
+func (f *File) accepted(u *url.URL, cookie *http.Cookie) bool {
+	target := &url.URL{Scheme: u.Scheme, Host: u.Host, Path: cookie.Path}
+
+	if domain := strings.TrimPrefix(cookie.Domain, "."); domain != "" {
+		target.Host = domain
+	}
+
+	if target.Path == "" || target.Path[0] != '/' {
+		target.Path = defaultPath(u.Path)
+	}
+
+	if cookie.Secure {
+		target.Scheme = "https"
+	}
+
+	for _, kept := range f.jar.Cookies(target) {
+		if kept.Name == cookie.Name && kept.Value == cookie.Value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// marshalLocked encodes the records, dropping the expired ones.
+func (f *File) marshalLocked() ([]byte, error) {
+	now := time.Now()
+
+	f.records = slices.DeleteFunc(f.records, func(rec record) bool { return rec.expired(now) })
+
+	data, err := json.MarshalIndent(f.records, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrSave, err)
+	}
+
+	return data, nil
+}
+
+// writeFile replaces the file at path atomically.
+func writeFile(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrSave, err)
+	}
+
+	_, err = tmp.Write(data)
+
+	if err == nil {
+		err = tmp.Chmod(filePerm)
+	}
+
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("%w: %w", ErrSave, err)
+	}
+
+	return nil
+}
+
+// newRecord stores cookie with an absolute expiry, so it expires at the same
+// time once loaded again.
+func newRecord(u *url.URL, cookie *http.Cookie, now time.Time) record {
+	rec := record{
+		URL:         (&url.URL{Scheme: u.Scheme, Host: u.Host, Path: u.Path}).String(),
+		Name:        cookie.Name,
+		Value:       cookie.Value,
+		Quoted:      cookie.Quoted,
+		Domain:      cookie.Domain,
+		Path:        cookie.Path,
+		Secure:      cookie.Secure,
+		HttpOnly:    cookie.HttpOnly,
+		SameSite:    cookie.SameSite,
+		Partitioned: cookie.Partitioned,
+	}
+
+	switch {
+	case cookie.MaxAge < 0:
+		rec.Expires = time.Unix(1, 0)
+	case cookie.MaxAge > 0:
+		rec.Expires = now.Add(time.Duration(cookie.MaxAge) * time.Second)
+	case !cookie.Expires.IsZero():
+		rec.Expires = cookie.Expires
+	}
+
+	rec.id = rec.key(u)
+
+	return rec
+}
+
+func (r record) cookie() *http.Cookie {
+	return &http.Cookie{
+		Name:        r.Name,
+		Value:       r.Value,
+		Quoted:      r.Quoted,
+		Domain:      r.Domain,
+		Path:        r.Path,
+		Expires:     r.Expires,
+		Secure:      r.Secure,
+		HttpOnly:    r.HttpOnly,
+		SameSite:    r.SameSite,
+		Partitioned: r.Partitioned,
+	}
+}
+
+func (r record) expired(now time.Time) bool {
+	return !r.Expires.IsZero() && !r.Expires.After(now)
+}
+
+// key identifies the cookie as the jar does: by name, domain, or host for
+// host-only cookies, and path.
+func (r record) key(u *url.URL) string {
+	domain := strings.ToLower(strings.TrimPrefix(r.Domain, "."))
+	if domain == "" {
+		domain = "host:" + strings.ToLower(u.Hostname())
+	}
+
+	path := r.Path
+	if path == "" || path[0] != '/' {
+		path = defaultPath(u.Path)
+	}
+
+	return domain + ";" + path + ";" + r.Name
+}
+
+// defaultPath is the default cookie path of a request path, as defined by
+// RFC 6265 section 5.1.4.
+func defaultPath(path string) string {
+	i := strings.LastIndex(path, "/")
+	if i <= 0 {
+		return "/"
+	}
+
+	return path[:i]
+}
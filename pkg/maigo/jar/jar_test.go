@@ -0,0 +1,198 @@
+package jar
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func names(cookies []*http.Cookie) []string {
+	var out []string
+	for _, cookie := range cookies {
+		out = append(out, cookie.Name+"="+cookie.Value)
+	}
+
+	slices.Sort(out)
+
+	return out
+}
+
+func mustParse(t *testing.T, raw string) *url.URL {
+	t.Helper()
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		t.Fatalf("url.Parse(%q) error = %v", raw, err)
+	}
+
+	return u
+}
+
+func TestNew_RejectsPublicSuffixDomains(t *testing.T) {
+	t.Parallel()
+
+	jar := New()
+
+	jar.SetCookies(mustParse(t, "https://shop.example.co.uk/"), []*http.Cookie{
+		{Name: "registry", Value: "1", Domain: "co.uk"},
+		{Name: "site", Value: "1", Domain: "example.co.uk"},
+	})
+
+	if got := names(jar.Cookies(mustParse(t, "https://other.co.uk/"))); len(got) != 0 {
+		t.Fatalf("Cookies(other.co.uk) = %v, want none", got)
+	}
+
+	if got := names(jar.Cookies(mustParse(t, "https://www.example.co.uk/"))); !slices.Equal(got, []string{"site=1"}) {
+		t.Fatalf("Cookies(www.example.co.uk) = %v, want [site=1]", got)
+	}
+}
+
+func TestNewFile_PersistsAcrossLoads(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "cookies.json")
+	site := mustParse(t, "https://example.com/account/login")
+
+	jar, err := NewFile(path)
+	if err != nil {
+		t.Fatalf("NewFile() error = %v", err)
+	}
+
+	jar.SetCookies(site, []*http.Cookie{
+		{Name: "session", Value: "abc", Path: "/"},
+		{Name: "remember", Value: "yes", Path: "/", MaxAge: 3600},
+		{Name: "flash", Value: "hi", Path: "/"},
+		{Name: "stale", Value: "old", Path: "/", Expires: time.Now().Add(-time.Hour)},
+	})
+	// replaced, then deleted
+	jar.SetCookies(site, []*http.Cookie{
+		{Name: "session", Value: "def", Path: "/"},
+		{Name: "flash", Path: "/", MaxAge: -1},
+	})
+
+	if err := jar.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Stat() error = %v", err)
+	}
+
+	if perm := info.Mode().Perm(); perm != filePerm {
+		t.Fatalf("file mode = %v, want %v", perm, os.FileMode(filePerm))
+	}
+
+	loaded, err := NewFile(path)
+	if err != nil {
+		t.Fatalf("NewFile() reload error = %v", err)
+	}
+
+	want := []string{"remember=yes", "session=def"}
+	if got := names(loaded.Cookies(mustParse(t, "https://example.com/"))); !slices.Equal(got, want) {
+		t.Fatalf("Cookies() after reload = %v, want %v", got, want)
+	}
+
+	if got := names(loaded.Cookies(mustParse(t, "https://other.example/"))); len(got) != 0 {
+		t.Fatalf("Cookies(other host) = %v, want none", got)
+	}
+
+	if err := loaded.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+}
+
+func TestNewFile_SavesOnlyAcceptedCookies(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "cookies.json")
+	site := mustParse(t, "https://www.example.co.uk/")
+
+	jar, err := NewFile(path)
+	if err != nil {
+		t.Fatalf("NewFile() error = %v", err)
+	}
+
+	jar.SetCookies(site, []*http.Cookie{
+		{Name: "session", Value: "abc", Domain: "example.co.uk"},
+		{Name: "registry", Value: "evil", Domain: "co.uk"},
+		{Name: "foreign", Value: "evil", Domain: "other.example"},
+	})
+
+	if err := jar.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+
+	if strings.Contains(string(data), "evil") || !strings.Contains(string(data), "session") {
+		t.Fatalf("saved cookies = %s, want only session", data)
+	}
+}
+
+func TestNewFile_SavesInBackground(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	site := mustParse(t, "https://example.com/")
+
+	saved, err := NewFile(filepath.Join(dir, "cookies.json"))
+	if err != nil {
+		t.Fatalf("NewFile() error = %v", err)
+	}
+
+	failing, err := NewFile(filepath.Join(dir, "missing", "cookies.json"))
+	if err != nil {
+		t.Fatalf("NewFile() error = %v", err)
+	}
+
+	for _, jar := range []*File{saved, failing} {
+		jar.delay = time.Millisecond
+		jar.SetCookies(site, []*http.Cookie{{Name: "session", Value: "abc"}})
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+
+	for {
+		_, statErr := os.Stat(saved.Path())
+		if statErr == nil && failing.Err() != nil {
+			break
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatalf("background saves: Stat() error = %v, Err() = %v", statErr, failing.Err())
+		}
+
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if err := failing.Err(); !errors.Is(err, ErrSave) {
+		t.Fatalf("Err() = %v, want ErrSave", err)
+	}
+
+	if err := saved.Err(); err != nil {
+		t.Fatalf("Err() = %v, want nil", err)
+	}
+}
+
+func TestNewFile_InvalidFile(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "cookies.json")
+	if err := os.WriteFile(path, []byte("not json"), filePerm); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := NewFile(path); !errors.Is(err, ErrLoad) {
+		t.Fatalf("NewFile() error = %v, want ErrLoad", err)
+	}
+}
@@ -0,0 +1,138 @@
+package maigo
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/jeanmolossi/maigo/pkg/maigo/contracts"
+	"github.com/jeanmolossi/maigo/pkg/maigo/header"
+	"github.com/jeanmolossi/maigo/pkg/maigo/mime"
+)
+
+type mediaTypeNote struct {
+	Text string `xml:"text"`
+}
+
+func TestMediaType_Auto(t *testing.T) {
+	t.Parallel()
+
+	post := func() contracts.RequestBuilder {
+		return DefaultClient("https://example.com").POST("/")
+	}
+
+	client := NewClient("https://example.com").
+		Header().Set(header.Accept, "application/problem+json").
+		Build()
+
+	tests := []struct {
+		name        string
+		build       func() (*http.Request, error)
+		contentType string
+		accept      string
+	}{
+		{
+			name:        "json",
+			build:       func() (*http.Request, error) { return post().Body().AsJSON(1).Unwrap() },
+			contentType: "application/json; charset=utf-8",
+			accept:      "application/json",
+		},
+		{
+			name:        "xml",
+			build:       func() (*http.Request, error) { return post().Body().AsXML(mediaTypeNote{}).Unwrap() },
+			contentType: "application/xml; charset=utf-8",
+			accept:      "application/xml",
+		},
+		{
+			name:        "string",
+			build:       func() (*http.Request, error) { return post().Body().AsString("hi").Unwrap() },
+			contentType: "text/plain; charset=utf-8",
+		},
+		{
+			name:  "reader",
+			build: func() (*http.Request, error) { return post().Body().AsReader(strings.NewReader("hi")).Unwrap() },
+		},
+		{
+			name: "headers set by the user win",
+			build: func() (*http.Request, error) {
+				return client.POST("/").
+					Header().Set(header.ContentType, "application/vnd.api+json").
+					Body().AsJSON(1).
+					Unwrap()
+			},
+			contentType: "application/vnd.api+json",
+			accept:      "application/problem+json",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req, err := tt.build()
+			if err != nil {
+				t.Fatalf("Unwrap() error = %v", err)
+			}
+
+			if got := req.Header.Get(header.ContentType.String()); got != tt.contentType {
+				t.Errorf("Content-Type = %q, want %q", got, tt.contentType)
+			}
+
+			if got := req.Header.Get(header.Accept.String()); got != tt.accept {
+				t.Errorf("Accept = %q, want %q", got, tt.accept)
+			}
+		})
+	}
+}
+
+func TestMediaType_Strict(t *testing.T) {
+	t.Parallel()
+
+	client := NewClient("https://example.com").
+		Config().SetMediaTypeMode(contracts.MediaTypeStrict).
+		Build()
+
+	_, err := client.POST("/").
+		Header().Set(header.ContentType, mime.XML.String()).
+		Body().AsJSON(1).
+		Unwrap()
+	if !errors.Is(err, ErrRequestValidation) || !errors.Is(err, ErrMediaTypeMismatch) {
+		t.Fatalf("Unwrap() error = %v, want %v", err, ErrMediaTypeMismatch)
+	}
+
+	// parameters do not make a mismatch
+	_, err = client.POST("/").
+		Header().Set(header.ContentType, mime.JSON.String()).
+		Body().AsJSON(1).
+		Unwrap()
+	if err != nil {
+		t.Fatalf("Unwrap() error = %v", err)
+	}
+}
+
+func TestMediaType_Off(t *testing.T) {
+	t.Parallel()
+
+	client := NewClient("https://example.com").
+		Config().SetMediaTypeMode(contracts.MediaTypeOff).
+		Build()
+
+	req, err := client.POST("/").Body().AsJSON(1).Unwrap()
+	if err != nil {
+		t.Fatalf("Unwrap() error = %v", err)
+	}
+
+	if req.Header.Get(header.ContentType.String()) != "" || req.Header.Get(header.Accept.String()) != "" {
+		t.Fatalf("headers = %v, want no Content-Type nor Accept", req.Header)
+	}
+
+	req, err = client.POST("/").Body().AsMultipart().Field("a", "b").Done().Unwrap()
+	if err != nil {
+		t.Fatalf("Unwrap() error = %v", err)
+	}
+
+	if got := req.Header.Get(header.ContentType.String()); !strings.HasPrefix(got, "multipart/form-data; boundary=") {
+		t.Fatalf("Content-Type = %q, want multipart/form-data with boundary", got)
+	}
+}
@@ -19,11 +19,13 @@ const (
 	BinaryData               Type = "application/octet-stream"
 	BitmapImage              Type = "image/bmp"
 	BourneShellScript        Type = "application/x-sh"
+	CBOR                     Type = "application/cbor"
 	CDAudio                  Type = "application/x-cdf"
 	CSS                      Type = "text/css"
 	CSV                      Type = "text/csv"
 	CShellScript             Type = "application/x-csh"
 	EPUB                     Type = "application/epub+zip"
+	EventStream              Type = "text/event-stream"
 	ExcelMacroEnabled        Type = "application/vnd.ms-excel.sheet.macroEnabled.12"
 	FormURLEncoded           Type = "application/x-www-form-urlencoded"
 	GIF                      Type = "image/gif"
@@ -35,6 +37,7 @@ const (
 	JSON                     Type = "application/json"
 	JSONAPI                  Type = "application/vnd.api+json"
 	JSONLD                   Type = "application/ld+json"
+	JSONLines                Type = "application/jsonl"
 	JavaArchive              Type = "application/java-archive"
 	JavaScript               Type = "text/javascript"
 	KeyArchive               Type = "application/pkcs12"
@@ -53,6 +56,8 @@ const (
 	MSWord                   Type = "application/msword"
 	MSWordOpenXML            Type = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
 	Markdown                 Type = "text/markdown"
+	MsgPack                  Type = "application/msgpack"
+	NDJSON                   Type = "application/x-ndjson"
 	OGG                      Type = "application/ogg"
 	OGGAudio                 Type = "audio/ogg"
 	OGGVideo                 Type = "video/ogg"
@@ -66,6 +71,8 @@ const (
 	PNG                      Type = "image/png"
 	PowerPointMacroEnabled   Type = "application/vnd.ms-powerpoint.presentation.macroEnabled.12"
 	PowerPointSlideshow      Type = "application/vnd.openxmlformats-officedocument.presentationml.slideshow"
+	ProblemJSON              Type = "application/problem+json"
+	Protobuf                 Type = "application/x-protobuf"
 	Quicktime                Type = "video/quicktime"
 	RARArchive               Type = "application/vnd.rar"
 	RichTextFormat           Type = "application/rtf"
@@ -93,6 +100,12 @@ const (
 	ZIPArchive               Type = "application/zip"
 )
 
+// WithCharset returns the media type with the given charset parameter, such
+// as "application/json; charset=utf-8".
+func (t Type) WithCharset(charset string) Type {
+	return t + "; charset=" + Type(charset)
+}
+
 // String returns the string representation of the Internet Media Type.
 func (t Type) String() string {
 	return string(t)
@@ -0,0 +1,395 @@
+package maigo
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// bodySource produces a request body on demand, so it can be streamed and
+// produced again when the request is replayed.
+type bodySource interface {
+	// open returns a new reader of the whole body.
+	open() (io.ReadCloser, error)
+	// size returns the body length in bytes or -1 when unknown.
+	size() int64
+}
+
+var _ bodySource = (*multipartBody)(nil)
+
+type (
+	// multipartBody is a multipart/form-data body streamed part by part.
+	multipartBody struct {
+		boundary string
+		parts    []*multipartPart
+	}
+
+	multipartPart struct {
+		header textproto.MIMEHeader
+		// open returns the part contents, the caller closes it.
+		open func() (io.ReadCloser, error)
+		// size is the contents length in bytes or -1 when unknown.
+		size int64
+		// stat, when set, returns the contents length when the body is
+		// sent, in place of size.
+		stat func() int64
+		// claim, when set, reserves the contents for the body being opened
+		// and fails while another body holds them, or once the contents of
+		// a one-shot reader were taken. release frees them when the body
+		// is done with.
+		claim func() (release func(), err error)
+	}
+)
+
+// open implements bodySource. The parts are only read once the returned
+// reader is, so an unread body holds no goroutine.
+func (m *multipartBody) open() (io.ReadCloser, error) {
+	var releases []func()
+
+	release := func() {
+		for _, partRelease := range releases {
+			partRelease()
+		}
+	}
+
+	// parts already taken fail before anything is sent rather than midway
+	for _, part := range m.parts {
+		if part.claim == nil {
+			continue
+		}
+
+		partRelease, err := part.claim()
+		if err != nil {
+			release()
+			return nil, err
+		}
+
+		releases = append(releases, partRelease)
+	}
+
+	return &lazyPipe{write: m.writeTo, release: sync.OnceFunc(release)}, nil
+}
+
+// size implements bodySource.
+func (m *multipartBody) size() int64 {
+	counter := &countingWriter{}
+	writer := newMultipartWriter(counter, m.boundary)
+
+	var contents int64
+
+	for _, part := range m.parts {
+		size := part.length()
+		if size < 0 {
+			return -1
+		}
+
+		if _, err := writer.CreatePart(part.header); err != nil {
+			return -1
+		}
+
+		contents += size
+	}
+
+	if err := writer.Close(); err != nil {
+		return -1
+	}
+
+	return counter.n + contents
+}
+
+// contentType returns the multipart/form-data media type with the boundary.
+func (m *multipartBody) contentType() string {
+	return "multipart/form-data; boundary=" + m.boundary
+}
+
+func (m *multipartBody) writeTo(w io.Writer) error {
+	writer := newMultipartWriter(w, m.boundary)
+
+	for _, part := range m.parts {
+		if err := m.writePart(writer, part); err != nil {
+			return err
+		}
+	}
+
+	return writer.Close()
+}
+
+func (m *multipartBody) writePart(writer *multipart.Writer, part *multipartPart) error {
+	dst, err := writer.CreatePart(part.header)
+	if err != nil {
+		return err
+	}
+
+	src, err := part.open()
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	_, err = io.Copy(dst, src)
+
+	return err
+}
+
+// length returns the contents length in bytes or -1 when unknown.
+func (p *multipartPart) length() int64 {
+	if p.stat != nil {
+		return p.stat()
+	}
+
+	return p.size
+}
+
+func (m *multipartBody) add(part *multipartPart) {
+	m.parts = append(m.parts, part)
+}
+
+func newMultipartBody() *multipartBody {
+	return &multipartBody{
+		// borrow a random boundary from the standard writer
+		boundary: multipart.NewWriter(io.Discard).Boundary(),
+	}
+}
+
+func newMultipartWriter(w io.Writer, boundary string) *multipart.Writer {
+	writer := multipart.NewWriter(w)
+	_ = writer.SetBoundary(boundary)
+
+	return writer
+}
+
+// newFieldPart creates a form field part.
+func newFieldPart(name, value string) *multipartPart {
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", formDataDisposition(name, ""))
+
+	return &multipartPart{
+		header: header,
+		open: func() (io.ReadCloser, error) {
+			return io.NopCloser(strings.NewReader(value)), nil
+		},
+		size: int64(len(value)),
+	}
+}
+
+// newFilePart creates a file part read from content. Seekable readers are
+// rewound when the part is produced again, other readers only once.
+func newFilePart(field, filename string, content io.Reader) *multipartPart {
+	return newReaderPart(fileHeader(field, filename), content)
+}
+
+// newReaderPart creates a part with custom headers read from content.
+func newReaderPart(header textproto.MIMEHeader, content io.Reader) *multipartPart {
+	part := &multipartPart{header: header, size: readerSize(content)}
+
+	seeker, seekable := content.(io.Seeker)
+
+	// readers such as *os.File and *bytes.Reader are read through their own
+	// section every time, so concurrent sends do not share an offset
+	if readerAt, ok := content.(io.ReaderAt); ok && seekable && part.size >= 0 {
+		if start, err := seeker.Seek(0, io.SeekCurrent); err == nil {
+			part.open = func() (io.ReadCloser, error) {
+				return io.NopCloser(io.NewSectionReader(readerAt, start, part.size)), nil
+			}
+
+			return part
+		}
+	}
+
+	var start int64
+	if seekable {
+		offset, err := seeker.Seek(0, io.SeekCurrent)
+		if err != nil {
+			seekable = false
+		}
+
+		start = offset
+	}
+
+	var claimed atomic.Bool
+
+	if !seekable {
+		part.claim = func() (func(), error) {
+			if !claimed.CompareAndSwap(false, true) {
+				return nil, fmt.Errorf("%w: part %q is read from a one-shot reader", ErrBodyNotReplayable, partName(header))
+			}
+
+			return func() {}, nil
+		}
+
+		part.open = func() (io.ReadCloser, error) {
+			return io.NopCloser(content), nil
+		}
+
+		return part
+	}
+
+	// bodies sharing the reader, those of clones included, would share its
+	// offset, so only one at a time reads it
+	part.claim = func() (func(), error) {
+		if !claimed.CompareAndSwap(false, true) {
+			return nil, fmt.Errorf("%w: part %q is being sent by another request", ErrBodyNotReplayable, partName(header))
+		}
+
+		return func() { claimed.Store(false) }, nil
+	}
+
+	var used atomic.Bool
+
+	part.open = func() (io.ReadCloser, error) {
+		if used.Swap(true) {
+			if _, err := seeker.Seek(start, io.SeekStart); err != nil {
+				return nil, err
+			}
+		}
+
+		return io.NopCloser(content), nil
+	}
+
+	return part
+}
+
+// newPathPart creates a file part reading the file at path, opened every
+// time the part is produced. Its size is read when the body is sent, so a
+// file changed in between is not sent with a stale length.
+func newPathPart(field, path string) (*multipartPart, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if info.IsDir() {
+		return nil, fmt.Errorf("%s is a directory", path)
+	}
+
+	return &multipartPart{
+		header: fileHeader(field, filepath.Base(path)),
+		open: func() (io.ReadCloser, error) {
+			return os.Open(path) //nolint:gosec // the caller chooses which file to upload
+		},
+		stat: func() int64 {
+			info, err := os.Stat(path)
+			if err != nil {
+				// unknown, opening the file reports the error
+				return -1
+			}
+
+			return info.Size()
+		},
+	}, nil
+}
+
+func fileHeader(field, filename string) textproto.MIMEHeader {
+	contentType := mime.TypeByExtension(filepath.Ext(filename))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", formDataDisposition(field, filename))
+	header.Set("Content-Type", contentType)
+
+	return header
+}
+
+func formDataDisposition(name, filename string) string {
+	params := map[string]string{"name": name}
+	if filename != "" {
+		params["filename"] = filename
+	}
+
+	return mime.FormatMediaType("form-data", params)
+}
+
+func partName(header textproto.MIMEHeader) string {
+	_, params, err := mime.ParseMediaType(header.Get("Content-Disposition"))
+	if err != nil {
+		return ""
+	}
+
+	return params["name"]
+}
+
+// readerSize returns the bytes left in r or -1 when they cannot be known
+// without reading it.
+func readerSize(r io.Reader) int64 {
+	switch v := r.(type) {
+	case interface{ Len() int }:
+		return int64(v.Len())
+	case io.Seeker:
+		current, err := v.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return -1
+		}
+
+		end, err := v.Seek(0, io.SeekEnd)
+		if err != nil {
+			return -1
+		}
+
+		if _, err := v.Seek(current, io.SeekStart); err != nil {
+			return -1
+		}
+
+		return end - current
+	default:
+		return -1
+	}
+}
+
+// lazyPipe streams what write produces, starting it on the first Read.
+// release is called once write returns, or when the pipe is closed unread.
+type lazyPipe struct {
+	write   func(w io.Writer) error
+	release func()
+
+	once   sync.Once
+	reader *io.PipeReader
+}
+
+func (l *lazyPipe) start() {
+	l.once.Do(func() {
+		reader, writer := io.Pipe()
+		l.reader = reader
+
+		go func() {
+			err := l.write(writer)
+			// released before the reader sees the end, so a replay that
+			// follows finds the parts free
+			l.release()
+			writer.CloseWithError(err)
+		}()
+	})
+}
+
+func (l *lazyPipe) Read(p []byte) (int, error) {
+	l.start()
+	return l.reader.Read(p)
+}
+
+func (l *lazyPipe) Close() error {
+	// a body closed before being read never starts the writer
+	l.once.Do(l.release)
+
+	if l.reader == nil {
+		return nil
+	}
+
+	return l.reader.Close()
+}
+
+type countingWriter struct {
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	c.n += int64(len(p))
+	return len(p), nil
+}
@@ -0,0 +1,256 @@
+package maigo
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// uploadRecord is what a test server saw in a multipart request.
+type uploadRecord struct {
+	contentLength int64
+	chunked       bool
+	fields        map[string]string
+	files         map[string]string
+}
+
+// uploads decodes the multipart requests server received.
+func uploads(t *testing.T, server *testServer) []uploadRecord {
+	t.Helper()
+
+	var records []uploadRecord
+
+	for _, request := range server.Requests() {
+		record := uploadRecord{
+			contentLength: request.contentLength,
+			chunked:       request.chunked,
+			fields:        map[string]string{},
+			files:         map[string]string{},
+		}
+
+		_, params, err := mime.ParseMediaType(request.header.Get("Content-Type"))
+		if err != nil {
+			t.Fatalf("ParseMediaType() error = %v", err)
+		}
+
+		reader := multipart.NewReader(bytes.NewReader(request.body), params["boundary"])
+
+		for {
+			part, err := reader.NextPart()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+
+			if err != nil {
+				t.Fatalf("NextPart() error = %v", err)
+			}
+
+			content, _ := io.ReadAll(part)
+			if part.FileName() != "" {
+				record.files[part.FormName()] = part.FileName() + ":" + string(content)
+			} else {
+				record.fields[part.FormName()] = string(content)
+			}
+		}
+
+		records = append(records, record)
+	}
+
+	return records
+}
+
+func writeTempFile(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	return path
+}
+
+func TestMultipart_KnownSizes(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer(t, respond(http.StatusOK, ""))
+	path := writeTempFile(t, "report.txt", "quarterly numbers")
+
+	resp, err := DefaultClient(server.URL).
+		POST("/uploads").
+		Body().AsMultipart().
+		Field("title", "Q1").
+		File("avatar", "mai.png", strings.NewReader("png-bytes")).
+		FilePath("report", path).
+		Done().
+		Send()
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	if !resp.Status().IsOK() {
+		t.Fatalf("status = %d, want 200", resp.Status().Code())
+	}
+
+	got := uploads(t, server)[0]
+
+	if got.contentLength <= 0 || got.chunked {
+		t.Fatalf("Content-Length = %d, chunked = %v, want a known length", got.contentLength, got.chunked)
+	}
+
+	if got.fields["title"] != "Q1" ||
+		got.files["avatar"] != "mai.png:png-bytes" ||
+		got.files["report"] != "report.txt:quarterly numbers" {
+		t.Fatalf("parts = %v %v", got.fields, got.files)
+	}
+}
+
+func TestMultipart_UnknownSizeIsChunked(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer(t, respond(http.StatusOK, ""))
+
+	_, err := DefaultClient(server.URL).
+		POST("/uploads").
+		Body().AsMultipart().
+		File("log", "app.log", io.MultiReader(strings.NewReader("line 1\n"), strings.NewReader("line 2\n"))).
+		Done().
+		Send()
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	got := uploads(t, server)[0]
+	if !got.chunked || got.files["log"] != "app.log:line 1\nline 2\n" {
+		t.Fatalf("chunked = %v, files = %v", got.chunked, got.files)
+	}
+}
+
+func TestMultipart_FilePathChangedAfterBuild(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer(t, respond(http.StatusOK, ""))
+	path := writeTempFile(t, "report.txt", "draft")
+
+	req := DefaultClient(server.URL).
+		POST("/uploads").
+		Body().AsMultipart().
+		FilePath("report", path).
+		Done()
+
+	// the file grows between building and sending the request
+	if err := os.WriteFile(path, []byte("final quarterly numbers"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := req.Send(); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	if got := uploads(t, server)[0].files["report"]; got != "report.txt:final quarterly numbers" {
+		t.Fatalf("files[report] = %q, want the current contents", got)
+	}
+}
+
+func TestMultipart_RetryReopensSources(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer(t, inTurn(respond(http.StatusInternalServerError, ""), respond(http.StatusOK, "")))
+	path := writeTempFile(t, "report.txt", "quarterly numbers")
+
+	resp, err := DefaultClient(server.URL).
+		POST("/uploads").
+		Body().AsMultipart().
+		FilePath("report", path).
+		File("notes", "notes.txt", strings.NewReader("seekable")).
+		Done().
+		Retry().SetConstantBackoff(time.Millisecond, 2).
+		Send()
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	if !resp.Status().IsOK() {
+		t.Fatalf("status = %d, want 200", resp.Status().Code())
+	}
+
+	for i, record := range uploads(t, server) {
+		if record.files["report"] != "report.txt:quarterly numbers" || record.files["notes"] != "notes.txt:seekable" {
+			t.Fatalf("attempt %d files = %v", i+1, record.files)
+		}
+	}
+}
+
+func TestMultipart_OneShotReaderIsNotReplayed(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer(t, inTurn(respond(http.StatusInternalServerError, ""), respond(http.StatusOK, "")))
+
+	_, err := DefaultClient(server.URL).
+		POST("/uploads").
+		Body().AsMultipart().
+		File("log", "app.log", io.MultiReader(strings.NewReader("once"))).
+		Done().
+		Retry().SetConstantBackoff(time.Millisecond, 2).
+		Send()
+	if !errors.Is(err, ErrBodyNotReplayable) {
+		t.Fatalf("Send() error = %v, want %v", err, ErrBodyNotReplayable)
+	}
+}
+
+func TestMultipart_UnwrapDoesNotTakeOneShotReader(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer(t, respond(http.StatusOK, ""))
+
+	req := DefaultClient(server.URL).
+		POST("/uploads").
+		Body().AsMultipart().
+		File("log", "app.log", io.MultiReader(strings.NewReader("once"))).
+		Done()
+
+	// building a request to inspect it does not read the part
+	if _, err := req.Unwrap(); err != nil {
+		t.Fatalf("Unwrap() error = %v", err)
+	}
+
+	if _, err := req.Send(); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	if got := uploads(t, server)[0].files["log"]; got != "app.log:once" {
+		t.Fatalf("files[log] = %q, want the whole part", got)
+	}
+
+	// reading the body of an unwrapped request takes the part
+	unwrapped, err := req.Unwrap()
+	if err != nil {
+		t.Fatalf("Unwrap() error = %v", err)
+	}
+
+	if _, err := io.ReadAll(unwrapped.Body); !errors.Is(err, ErrBodyNotReplayable) {
+		t.Fatalf("ReadAll() error = %v, want %v", err, ErrBodyNotReplayable)
+	}
+}
+
+func TestMultipart_MissingFile(t *testing.T) {
+	t.Parallel()
+
+	_, err := DefaultClient("https://example.com").
+		POST("/uploads").
+		Body().AsMultipart().
+		FilePath("report", filepath.Join(t.TempDir(), "missing.txt")).
+		Done().
+		Unwrap()
+	if !errors.Is(err, ErrToSetBody) || !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("Unwrap() error = %v, want %v", err, os.ErrNotExist)
+	}
+}
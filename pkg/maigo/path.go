@@ -0,0 +1,60 @@
+package maigo
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+)
+
+// pathPlaceholder matches the {name} placeholders of a path template.
+var pathPlaceholder = regexp.MustCompile(`\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// expandPath fills the placeholders of template with params, escaping each
+// value as a single path segment. The result is an escaped path.
+func expandPath(template string, params map[string]string) (string, error) {
+	var missing error
+
+	used := make(map[string]bool, len(params))
+
+	path := pathPlaceholder.ReplaceAllStringFunc(template, func(placeholder string) string {
+		name := placeholder[1 : len(placeholder)-1]
+
+		value, ok := params[name]
+		if !ok || value == "" {
+			if missing == nil {
+				missing = fmt.Errorf("%w: %q in %q", ErrMissingPathParam, name, template)
+			}
+
+			return placeholder
+		}
+
+		used[name] = true
+
+		return escapePathSegment(value)
+	})
+
+	if missing != nil {
+		return "", missing
+	}
+
+	for name := range params {
+		if !used[name] {
+			return "", fmt.Errorf("%w: %q in %q", ErrUnknownPathParam, name, template)
+		}
+	}
+
+	return path, nil
+}
+
+// escapePathSegment escapes value so it stays a single segment, including the
+// dot segments that would otherwise be resolved against the parent path.
+func escapePathSegment(value string) string {
+	switch value {
+	case ".":
+		return "%2E"
+	case "..":
+		return "%2E%2E"
+	default:
+		return url.PathEscape(value)
+	}
+}
@@ -0,0 +1,79 @@
+package maigo
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jeanmolossi/maigo/pkg/httpx"
+	"github.com/jeanmolossi/maigo/pkg/maigo/contracts"
+)
+
+func TestPath_EscapesParams(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{name: "plain", value: "42", want: "/api/users/42/orders"},
+		{name: "slash", value: "a/b", want: "/api/users/a%2Fb/orders"},
+		{name: "space", value: "mai sakurajima", want: "/api/users/mai%20sakurajima/orders"},
+		{name: "dot segment", value: "..", want: "/api/users/%2E%2E/orders"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req, err := DefaultClient("https://example.com/api").
+				GET("/users/{id}/orders").
+				Path().Param("id", tt.value).
+				Unwrap()
+			if err != nil {
+				t.Fatalf("Unwrap() error = %v", err)
+			}
+
+			if got := req.URL.EscapedPath(); got != tt.want {
+				t.Fatalf("EscapedPath() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPath_KeepsRouteInContext(t *testing.T) {
+	t.Parallel()
+
+	req, err := DefaultClient("https://example.com").
+		GET("/users/{id}/orders/{orderId}").
+		Path().Params(contracts.Params{"id": "1", "orderId": "2"}).
+		Unwrap()
+	if err != nil {
+		t.Fatalf("Unwrap() error = %v", err)
+	}
+
+	if got := req.URL.Path; got != "/users/1/orders/2" {
+		t.Fatalf("Path = %q, want %q", got, "/users/1/orders/2")
+	}
+
+	route, ok := httpx.RouteFromContext(req.Context())
+	if !ok || route != "/users/{id}/orders/{orderId}" {
+		t.Fatalf("RouteFromContext() = %q, %v, want %q", route, ok, "/users/{id}/orders/{orderId}")
+	}
+}
+
+func TestPath_Validation(t *testing.T) {
+	t.Parallel()
+
+	client := DefaultClient("https://example.com")
+
+	_, err := client.GET("/users/{id}/orders/{orderId}").Path().Param("id", "1").Unwrap()
+	if !errors.Is(err, ErrRequestValidation) || !errors.Is(err, ErrMissingPathParam) {
+		t.Fatalf("Unwrap() error = %v, want %v", err, ErrMissingPathParam)
+	}
+
+	_, err = client.GET("/users/{id}").Path().Param("id", "1").Path().Param("name", "mai").Unwrap()
+	if !errors.Is(err, ErrUnknownPathParam) {
+		t.Fatalf("Unwrap() error = %v, want %v", err, ErrUnknownPathParam)
+	}
+}
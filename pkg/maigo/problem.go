@@ -0,0 +1,177 @@
+package maigo
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/jeanmolossi/maigo/pkg/maigo/contracts"
+	"github.com/jeanmolossi/maigo/pkg/maigo/header"
+	"github.com/jeanmolossi/maigo/pkg/maigo/mime"
+)
+
+// aboutBlank is the problem type when none is given.
+const aboutBlank = "about:blank"
+
+// problemMembers are the members defined by RFC 9457, kept out of the
+// extension map.
+var problemMembers = []string{"type", "title", "status", "detail", "instance"}
+
+// Problem is an RFC 9457 Problem Details document with its extension
+// members in a map. It is an error, so requests sent with ExpectSuccess
+// answered with application/problem+json can be inspected with errors.As:
+//
+//	var problem *maigo.Problem
+//	if errors.As(err, &problem) {
+//	    log.Print(problem.Title, problem.Extensions["balance"])
+//	}
+type Problem = ProblemOf[map[string]any]
+
+// ProblemOf is a Problem Details document whose extension members are
+// decoded into X, usually a struct with json tags:
+//
+//	type OutOfCredit struct {
+//	    Balance  int      `json:"balance"`
+//	    Accounts []string `json:"accounts"`
+//	}
+//
+//	problem, err := maigo.DecodeProblem[OutOfCredit](resp)
+type ProblemOf[X any] struct {
+	// Type identifies the problem type, "about:blank" when absent.
+	Type string
+	// Title is a short summary of the problem type.
+	Title string
+	// Status is the status code set by the server, zero when absent.
+	Status int
+	// Detail explains this occurrence of the problem.
+	Detail string
+	// Instance identifies this occurrence of the problem.
+	Instance string
+	// Extensions holds the members beyond the ones above.
+	Extensions X
+}
+
+// Error implements error.
+func (p *ProblemOf[X]) Error() string {
+	title := p.Title
+	if title == "" {
+		title = p.Type
+	}
+
+	msg := "problem: " + title
+
+	if p.Status != 0 {
+		msg = fmt.Sprintf("%s (%d)", msg, p.Status)
+	}
+
+	if p.Detail != "" {
+		msg += ": " + p.Detail
+	}
+
+	return msg
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Members of the wrong type are
+// ignored, as RFC 9457 requires.
+func (p *ProblemOf[X]) UnmarshalJSON(data []byte) error {
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(data, &members); err != nil {
+		return err
+	}
+
+	// a member of an unexpected type leaves the zero value
+	_ = json.Unmarshal(members["type"], &p.Type)
+	_ = json.Unmarshal(members["title"], &p.Title)
+	_ = json.Unmarshal(members["status"], &p.Status)
+	_ = json.Unmarshal(members["detail"], &p.Detail)
+	_ = json.Unmarshal(members["instance"], &p.Instance)
+
+	if p.Type == "" {
+		p.Type = aboutBlank
+	}
+
+	if err := json.Unmarshal(data, &p.Extensions); err != nil {
+		return err
+	}
+
+	if extensions, ok := any(p.Extensions).(map[string]any); ok {
+		for _, member := range problemMembers {
+			delete(extensions, member)
+		}
+	}
+
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler, flattening the extension members
+// into the document.
+func (p ProblemOf[X]) MarshalJSON() ([]byte, error) {
+	document := map[string]any{}
+
+	data, err := json.Marshal(p.Extensions)
+	if err != nil {
+		return nil, err
+	}
+
+	// extensions that are not objects cannot be flattened
+	if string(data) != "null" {
+		if err := json.Unmarshal(data, &document); err != nil {
+			return nil, fmt.Errorf("problem extensions must encode as an object: %w", err)
+		}
+	}
+
+	for member, value := range map[string]any{
+		"type": p.Type, "title": p.Title, "detail": p.Detail, "instance": p.Instance,
+	} {
+		if value != "" {
+			document[member] = value
+		}
+	}
+
+	if p.Status != 0 {
+		document["status"] = p.Status
+	}
+
+	return json.Marshal(document)
+}
+
+// DecodeProblem decodes the Problem Details document of resp, with its
+// extension members decoded into X, and closes the body. It fails with
+// ErrNotProblem when resp is not application/problem+json.
+func DecodeProblem[X any](resp contracts.Response) (*ProblemOf[X], error) {
+	defer resp.Body().Close()
+
+	if !resp.Body().IsProblem() {
+		return nil, fmt.Errorf("%w: %q", ErrNotProblem, resp.Header().Get(header.ContentType.String()))
+	}
+
+	problem := &ProblemOf[X]{}
+	if err := json.NewDecoder(responseReader(resp)).Decode(problem); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrToDecodeBody, err)
+	}
+
+	return problem, nil
+}
+
+// ProblemExtensions decodes the extension members of problem into X, for
+// problems obtained through errors.As.
+func ProblemExtensions[X any](problem *Problem) (X, error) {
+	var extensions X
+
+	data, err := json.Marshal(problem.Extensions)
+	if err != nil {
+		return extensions, err
+	}
+
+	if err := json.Unmarshal(data, &extensions); err != nil {
+		return extensions, fmt.Errorf("%w: %w", ErrToDecodeBody, err)
+	}
+
+	return extensions, nil
+}
+
+func isProblemType(contentType string) bool {
+	mediaType, _, _ := strings.Cut(contentType, ";")
+
+	return strings.EqualFold(strings.TrimSpace(mediaType), mime.ProblemJSON.String())
+}
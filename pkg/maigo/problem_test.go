@@ -0,0 +1,155 @@
+package maigo
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+const outOfCreditProblem = `{
+	"type": "https://example.com/probs/out-of-credit",
+	"title": "You do not have enough credit.",
+	"status": 403,
+	"detail": "Your current balance is 30, but that costs 50.",
+	"instance": "/account/12345/msgs/abc",
+	"balance": 30,
+	"accounts": ["/account/12345", "/account/67890"]
+}`
+
+type outOfCredit struct {
+	Balance  int      `json:"balance"`
+	Accounts []string `json:"accounts"`
+}
+
+func TestDecodeProblem(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer(t, respond(http.StatusForbidden, outOfCreditProblem, "Content-Type", "application/problem+json; charset=utf-8"))
+
+	resp, err := DefaultClient(server.URL).GET("/").Send()
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	if !resp.Body().IsProblem() {
+		t.Fatal("IsProblem() = false, want true")
+	}
+
+	problem, err := DecodeProblem[map[string]any](resp)
+	if err != nil {
+		t.Fatalf("DecodeProblem() error = %v", err)
+	}
+
+	if problem.Type != "https://example.com/probs/out-of-credit" || problem.Status != http.StatusForbidden ||
+		problem.Instance != "/account/12345/msgs/abc" {
+		t.Fatalf("problem = %+v", problem)
+	}
+
+	if len(problem.Extensions) != 2 || problem.Extensions["balance"] != float64(30) {
+		t.Fatalf("Extensions = %v, want balance and accounts only", problem.Extensions)
+	}
+
+	want := "problem: You do not have enough credit. (403): Your current balance is 30, but that costs 50."
+	if problem.Error() != want {
+		t.Fatalf("Error() = %q, want %q", problem.Error(), want)
+	}
+}
+
+func TestDecodeProblem_TypedExtensions(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer(t, respond(http.StatusForbidden, outOfCreditProblem, "Content-Type", "application/problem+json"))
+
+	resp, err := DefaultClient(server.URL).GET("/").Send()
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	problem, err := DecodeProblem[outOfCredit](resp)
+	if err != nil {
+		t.Fatalf("DecodeProblem() error = %v", err)
+	}
+
+	if problem.Extensions.Balance != 30 || len(problem.Extensions.Accounts) != 2 {
+		t.Fatalf("Extensions = %+v", problem.Extensions)
+	}
+}
+
+func TestDecodeProblem_Lenient(t *testing.T) {
+	t.Parallel()
+
+	var problem Problem
+
+	if err := json.Unmarshal([]byte(`{"status":"403","title":"Forbidden"}`), &problem); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	if problem.Type != "about:blank" || problem.Status != 0 || problem.Title != "Forbidden" {
+		t.Fatalf("problem = %+v, want about:blank and the invalid status ignored", problem)
+	}
+
+	data, err := json.Marshal(ProblemOf[outOfCredit]{Title: "Out of credit", Status: 403, Extensions: outOfCredit{Balance: 30}})
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	var decoded map[string]any
+	_ = json.Unmarshal(data, &decoded)
+
+	if decoded["title"] != "Out of credit" || decoded["status"] != float64(403) || decoded["balance"] != float64(30) {
+		t.Fatalf("Marshal() = %s, want flattened members", data)
+	}
+}
+
+func TestDecodeProblem_NotProblem(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer(t, respond(http.StatusForbidden, `{"title":"nope"}`, "Content-Type", "application/json"))
+
+	resp, err := DefaultClient(server.URL).GET("/").Send()
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	if _, err := DecodeProblem[map[string]any](resp); !errors.Is(err, ErrNotProblem) {
+		t.Fatalf("DecodeProblem() error = %v, want %v", err, ErrNotProblem)
+	}
+}
+
+func TestProblem_ErrorsAs(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer(t, respond(http.StatusForbidden, outOfCreditProblem, "Content-Type", "application/problem+json"))
+
+	_, err := DefaultClient(server.URL).GET("/").ExpectSuccess().Send()
+
+	var problem *Problem
+	if !errors.As(err, &problem) {
+		t.Fatalf("Send() error = %v, want a *Problem", err)
+	}
+
+	if problem.Status != http.StatusForbidden || !errors.Is(err, ErrUnexpectedStatus) {
+		t.Fatalf("problem = %+v", problem)
+	}
+
+	extensions, err := ProblemExtensions[outOfCredit](problem)
+	if err != nil || extensions.Balance != 30 {
+		t.Fatalf("ProblemExtensions() = %+v, %v", extensions, err)
+	}
+}
+
+func TestProblem_Do(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer(t, respond(http.StatusForbidden, outOfCreditProblem, "Content-Type", "application/problem+json"))
+
+	_, apiErr, err := Do[map[string]any, ProblemOf[outOfCredit]](DefaultClient(server.URL).GET("/"))
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+
+	if apiErr == nil || apiErr.Body.Extensions.Balance != 30 || apiErr.Problem == nil {
+		t.Fatalf("apiErr = %+v, want the typed problem", apiErr)
+	}
+}
@@ -0,0 +1,121 @@
+package maigo
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/jeanmolossi/maigo/pkg/maigo/contracts"
+)
+
+// progressHook reports the progress of the bodies it tracks to fn, at most
+// once per interval.
+type progressHook struct {
+	fn       contracts.ProgressFunc
+	interval time.Duration
+}
+
+func newProgressHook(fn contracts.ProgressFunc, interval time.Duration) *progressHook {
+	if fn == nil {
+		return nil
+	}
+
+	return &progressHook{fn: fn, interval: interval}
+}
+
+// track wraps body, whose size is total or -1 when unknown.
+func (h *progressHook) track(body io.ReadCloser, total int64) io.ReadCloser {
+	if body == nil || body == http.NoBody {
+		return body
+	}
+
+	return &progressReader{
+		ReadCloser: body,
+		hook:       h,
+		total:      total,
+		start:      time.Now(),
+	}
+}
+
+// trackRequest tracks the body of request, including the copies produced
+// when it is replayed.
+func (h *progressHook) trackRequest(request *http.Request) {
+	if request.Body == nil || request.Body == http.NoBody {
+		return
+	}
+
+	// a zero length with a body means the size is unknown
+	total := request.ContentLength
+	if total == 0 {
+		total = -1
+	}
+
+	request.Body = h.track(request.Body, total)
+
+	if getBody := request.GetBody; getBody != nil {
+		request.GetBody = func() (io.ReadCloser, error) {
+			body, err := getBody()
+			if err != nil {
+				return nil, err
+			}
+
+			return h.track(body, total), nil
+		}
+	}
+}
+
+// progressReader reports the bytes read through it. A body is read by a
+// single goroutine at a time, so it keeps no lock.
+type progressReader struct {
+	io.ReadCloser
+
+	hook        *progressHook
+	total       int64
+	transferred int64
+	start       time.Time
+	last        time.Time
+	done        bool
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.ReadCloser.Read(b)
+	if p.done {
+		return n, err
+	}
+
+	p.transferred += int64(n)
+
+	// the transport may stop reading once it sent the announced length
+	done := errors.Is(err, io.EOF) || p.total > 0 && p.transferred >= p.total
+
+	now := time.Now()
+	if n == 0 && !done || !done && now.Sub(p.last) < p.hook.interval {
+		return n, err
+	}
+
+	p.last = now
+	p.done = done
+
+	elapsed := now.Sub(p.start)
+
+	var rate float64
+	if elapsed > 0 {
+		rate = float64(p.transferred) / elapsed.Seconds()
+	}
+
+	p.hook.fn(contracts.Progress{
+		Transferred: p.transferred,
+		Total:       p.total,
+		Elapsed:     elapsed,
+		Rate:        rate,
+		Done:        done,
+	})
+
+	return n, err
+}
+
+// Unwrap returns the body p wraps.
+func (p *progressReader) Unwrap() io.ReadCloser {
+	return p.ReadCloser
+}
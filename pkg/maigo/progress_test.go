@@ -0,0 +1,131 @@
+package maigo
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jeanmolossi/maigo/async"
+	"github.com/jeanmolossi/maigo/pkg/maigo/contracts"
+)
+
+// progressRecorder collects the reports of a transfer.
+type progressRecorder struct {
+	mu      sync.Mutex
+	reports []contracts.Progress
+}
+
+func (p *progressRecorder) record(progress contracts.Progress) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.reports = append(p.reports, progress)
+}
+
+func (p *progressRecorder) assertDone(t *testing.T, total int64) {
+	t.Helper()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if len(p.reports) == 0 {
+		t.Fatal("no progress reported")
+	}
+
+	last := p.reports[len(p.reports)-1]
+	if !last.Done || last.Transferred != total || last.Total != total {
+		t.Fatalf("last report = %+v, want %d of %d bytes done", last, total, total)
+	}
+
+	if last.Percent() != 100 {
+		t.Fatalf("Percent() = %v, want 100", last.Percent())
+	}
+
+	for i := 1; i < len(p.reports); i++ {
+		if p.reports[i].Transferred < p.reports[i-1].Transferred || p.reports[i-1].Done {
+			t.Fatalf("reports out of order: %+v", p.reports)
+		}
+	}
+}
+
+func TestOnUploadProgress(t *testing.T) {
+	t.Parallel()
+
+	payload := strings.Repeat("x", 256<<10)
+	server := newTestServer(t, respond(http.StatusOK, ""))
+
+	var recorder progressRecorder
+
+	resp, err := DefaultClient(server.URL).
+		POST("/").
+		Body().AsString(payload).
+		Body().OnUploadProgress(recorder.record, 0).
+		Send()
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	resp.Body().Close()
+
+	recorder.assertDone(t, int64(len(payload)))
+}
+
+func TestOnDownloadProgress_AsyncDispatch(t *testing.T) {
+	t.Parallel()
+
+	payload := strings.Repeat("y", 256<<10)
+	server := newTestServer(t, respond(http.StatusOK, payload))
+
+	var recorder progressRecorder
+
+	result, err := async.Dispatch(DefaultClient(server.URL).
+		GET("/").
+		Body().OnDownloadProgress(recorder.record, 0))
+	if err != nil {
+		t.Fatalf("Dispatch() error = %v", err)
+	}
+
+	resp, err := result.Response()
+	if err != nil {
+		t.Fatalf("Response() error = %v", err)
+	}
+
+	if _, err := resp.Body().ToWriter(io.Discard); err != nil {
+		t.Fatalf("ToWriter() error = %v", err)
+	}
+
+	recorder.assertDone(t, int64(len(payload)))
+}
+
+func TestResponseBody_OnProgressInterval(t *testing.T) {
+	t.Parallel()
+
+	payload := strings.Repeat("z", 256<<10)
+	server := newTestServer(t, respond(http.StatusOK, payload))
+
+	resp, err := DefaultClient(server.URL).GET("/").Send()
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	var recorder progressRecorder
+
+	body, err := resp.Body().OnProgress(recorder.record, time.Hour).AsString()
+	if err != nil {
+		t.Fatalf("AsString() error = %v", err)
+	}
+
+	if body != payload {
+		t.Fatalf("AsString() read %d bytes, want %d", len(body), len(payload))
+	}
+
+	recorder.assertDone(t, int64(len(payload)))
+
+	// the first read and the completion only
+	if len(recorder.reports) != 2 {
+		t.Fatalf("reported %d times, want 2", len(recorder.reports))
+	}
+}
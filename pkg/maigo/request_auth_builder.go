@@ -0,0 +1,86 @@
+package maigo
+
+import (
+	"fmt"
+
+	"github.com/jeanmolossi/maigo/pkg/maigo/contracts"
+	"github.com/jeanmolossi/maigo/pkg/maigo/header"
+)
+
+var _ contracts.BuilderAuth[contracts.RequestBuilder] = (*RequestAuthBuilder)(nil)
+
+type RequestAuthBuilder struct {
+	parent *RequestBuilder
+	config *RequestConfigBase
+}
+
+func (r *RequestBuilder) Auth() contracts.BuilderAuth[contracts.RequestBuilder] {
+	return &RequestAuthBuilder{
+		parent: r,
+		config: r.request.config,
+	}
+}
+
+// Set implements contracts.BuilderAuth.
+func (r *RequestAuthBuilder) Set(value string) contracts.RequestBuilder {
+	auth, err := NewHeaderAuth(header.Authorization, value)
+	return r.apply(auth, err)
+}
+
+// BearerToken implements contracts.BuilderAuth.
+func (r *RequestAuthBuilder) BearerToken(token string) contracts.RequestBuilder {
+	auth, err := NewBearerAuth(token)
+	return r.apply(auth, err)
+}
+
+// BasicAuth implements contracts.BuilderAuth.
+func (r *RequestAuthBuilder) BasicAuth(user, pass string) contracts.RequestBuilder {
+	auth, err := NewBasicAuth(user, pass)
+	return r.apply(auth, err)
+}
+
+// APIKeyHeader implements contracts.BuilderAuth.
+func (r *RequestAuthBuilder) APIKeyHeader(key header.Type, value string) contracts.RequestBuilder {
+	auth, err := NewHeaderAuth(key, value)
+	return r.apply(auth, err)
+}
+
+// APIKeyQuery implements contracts.BuilderAuth.
+func (r *RequestAuthBuilder) APIKeyQuery(param, value string) contracts.RequestBuilder {
+	auth, err := NewQueryAuth(param, value)
+	return r.apply(auth, err)
+}
+
+// TokenSource implements contracts.BuilderAuth. Requests of a client given
+// the same source share its cached token.
+func (r *RequestAuthBuilder) TokenSource(source contracts.TokenSource) contracts.RequestBuilder {
+	if source == nil {
+		return r.apply(nil, fmt.Errorf("%w: nil token source", ErrInvalidAuth))
+	}
+
+	if client, ok := r.parent.request.client.(*ClientConfigBase); ok {
+		return r.apply(client.tokenSources.authFor(source), nil)
+	}
+
+	return r.apply(NewTokenSourceAuth(source), nil)
+}
+
+// Custom implements contracts.BuilderAuth.
+func (r *RequestAuthBuilder) Custom(auth contracts.Authenticator) contracts.RequestBuilder {
+	if auth == nil {
+		return r.apply(nil, fmt.Errorf("%w: nil authenticator", ErrInvalidAuth))
+	}
+
+	return r.apply(auth, nil)
+}
+
+func (r *RequestAuthBuilder) apply(auth contracts.Authenticator, err error) contracts.RequestBuilder {
+	if err != nil {
+		r.config.validations.Add(err)
+		return r.parent
+	}
+
+	r.config.auth = auth
+
+	return r.parent
+}
@@ -1,10 +1,16 @@
 package maigo
 
 import (
+	"bytes"
 	"errors"
+	"fmt"
 	"io"
+	"net/url"
+	"time"
 
+	"github.com/jeanmolossi/maigo/pkg/httpx/compression"
 	"github.com/jeanmolossi/maigo/pkg/maigo/contracts"
+	"github.com/jeanmolossi/maigo/pkg/maigo/mime"
 )
 
 var _ contracts.BuilderRequestBody[contracts.RequestBuilder] = (*RequestBodyBuilder)(nil)
@@ -26,18 +32,24 @@ func (r *RequestBodyBuilder) AsReader(body io.Reader) contracts.RequestBuilder {
 	err := r.config.body.Set(body)
 	if err != nil {
 		r.config.validations.Add(errors.Join(ErrToSetBody, err))
+		return r.parent
 	}
 
+	r.config.setBody("", "", nil)
+
 	return r.parent
 }
 
 // AsJSON implements contracts.BuilderRequestBody.
 func (r *RequestBodyBuilder) AsJSON(obj any) contracts.RequestBuilder {
-	err := r.config.body.WriteAsJSON(obj)
+	err := r.encode(mime.JSON, obj)
 	if err != nil {
 		r.config.validations.Add(errors.Join(ErrToMarshalJSON, err))
+		return r.parent
 	}
 
+	r.config.setBody(mime.JSON.WithCharset("utf-8"), mime.JSON, nil)
+
 	return r.parent
 }
 
@@ -46,17 +58,105 @@ func (r *RequestBodyBuilder) AsString(body string) contracts.RequestBuilder {
 	err := r.config.body.WriteAsString(body)
 	if err != nil {
 		r.config.validations.Add(errors.Join(ErrToSetBody, err))
+		return r.parent
+	}
+
+	r.config.setBody(mime.Text.WithCharset("utf-8"), "", nil)
+
+	return r.parent
+}
+
+// AsForm implements contracts.BuilderRequestBody.
+func (r *RequestBodyBuilder) AsForm(values url.Values) contracts.RequestBuilder {
+	err := r.config.body.WriteAsString(values.Encode())
+	if err != nil {
+		r.config.validations.Add(errors.Join(ErrToSetBody, err))
+		return r.parent
 	}
 
+	r.config.setBody(mime.FormURLEncoded, "", nil)
+
 	return r.parent
 }
 
+// AsFormStruct implements contracts.BuilderRequestBody.
+func (r *RequestBodyBuilder) AsFormStruct(obj any) contracts.RequestBuilder {
+	values, err := encodeForm(obj)
+	if err != nil {
+		r.config.validations.Add(errors.Join(ErrToMarshalForm, err))
+		return r.parent
+	}
+
+	return r.AsForm(values)
+}
+
 // AsXML implements contracts.BuilderRequestBody.
 func (r *RequestBodyBuilder) AsXML(obj any) contracts.RequestBuilder {
-	err := r.config.body.WriteAsXML(obj)
+	err := r.encode(mime.XML, obj)
 	if err != nil {
 		r.config.validations.Add(errors.Join(ErrToMarshalXML, err))
+		return r.parent
 	}
 
+	r.config.setBody(mime.XML.WithCharset("utf-8"), mime.XML, nil)
+
 	return r.parent
 }
+
+// As implements contracts.BuilderRequestBody.
+func (r *RequestBodyBuilder) As(mediaType mime.Type, obj any) contracts.RequestBuilder {
+	err := r.encode(mediaType, obj)
+	if err != nil {
+		r.config.validations.Add(err)
+		return r.parent
+	}
+
+	r.config.setBody(mediaType, mediaType, nil)
+
+	return r.parent
+}
+
+// Compress implements contracts.BuilderRequestBody.
+func (r *RequestBodyBuilder) Compress(encoding compression.Encoding, minSize int64) contracts.RequestBuilder {
+	if !encoding.Supported() {
+		r.config.validations.Add(fmt.Errorf("%w: %q", compression.ErrUnsupportedEncoding, encoding))
+		return r.parent
+	}
+
+	r.config.compression = encoding
+	r.config.compressionMinSize = minSize
+
+	return r.parent
+}
+
+// OnUploadProgress implements contracts.BuilderRequestBody.
+func (r *RequestBodyBuilder) OnUploadProgress(fn contracts.ProgressFunc, interval time.Duration) contracts.RequestBuilder {
+	r.config.uploadProgress = newProgressHook(fn, interval)
+	return r.parent
+}
+
+// OnDownloadProgress implements contracts.BuilderRequestBody.
+func (r *RequestBodyBuilder) OnDownloadProgress(fn contracts.ProgressFunc, interval time.Duration) contracts.RequestBuilder {
+	r.config.downloadProgress = newProgressHook(fn, interval)
+	return r.parent
+}
+
+// encode replaces the body with obj encoded by the codec of mediaType.
+func (r *RequestBodyBuilder) encode(mediaType mime.Type, obj any) error {
+	codec, ok := r.parent.codecs().Lookup(mediaType)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnsupportedMediaType, mediaType)
+	}
+
+	var buf bytes.Buffer
+
+	if err := codec.Encode(&buf, obj); err != nil {
+		return errors.Join(ErrToEncodeBody, err)
+	}
+
+	if err := r.config.body.Set(&buf); err != nil {
+		return errors.Join(ErrToSetBody, err)
+	}
+
+	return nil
+}
@@ -6,14 +6,18 @@ import (
 	"encoding/binary"
 	"errors"
 	"fmt"
+	"io"
 	"math"
 	mrand "math/rand"
+	"mime"
 	"net/http"
 	"net/url"
+	"slices"
 	"strconv"
 	"sync"
 	"time"
 
+	"github.com/jeanmolossi/maigo/pkg/httpx"
 	"github.com/jeanmolossi/maigo/pkg/maigo/contracts"
 	"github.com/jeanmolossi/maigo/pkg/maigo/header"
 )
@@ -27,11 +31,14 @@ var (
 
 type RequestBuilder struct {
 	request *Request
+	// release frees the base URL of the call being sent. It is only set on
+	// the copy of the builder a send works on.
+	release func()
 }
 
-func (r *RequestBuilder) createFullURL() *url.URL {
+func (r *RequestBuilder) createFullURL(baseURL *url.URL, path string) *url.URL {
 	// parse base URL and path
-	fullURL := r.request.client.BaseURL().JoinPath(r.request.config.Path())
+	fullURL := baseURL.JoinPath(path)
 
 	query := fullURL.Query()
 
@@ -46,23 +53,69 @@ func (r *RequestBuilder) createFullURL() *url.URL {
 	return fullURL
 }
 
-func (r *RequestBuilder) createHTTPRequest() (*http.Request, error) {
+// createHTTPRequest creates the request to send. Unless send is set, as for a
+// request built for inspection, streamed bodies are only opened once read.
+func (r *RequestBuilder) createHTTPRequest(ctx context.Context, baseURL *url.URL, path string, send bool) (*http.Request, error) {
 	// create full URL
-	fullURL := r.createFullURL()
+	fullURL := r.createFullURL(baseURL, path)
+
+	body := r.request.config.body.Unwrap()
+	source := r.request.config.bodySource
+
+	compressed, err := r.compressBody()
+	if err != nil {
+		return nil, errors.Join(ErrToSetBody, err)
+	}
+
+	if compressed != nil {
+		source = compressed
+	}
+
+	var open func() (io.ReadCloser, error)
+
+	if source != nil {
+		open = source.open
+		if !send {
+			open = openOnRead(source.open)
+		}
+
+		stream, err := open()
+		if err != nil {
+			return nil, errors.Join(ErrToSetBody, err)
+		}
+
+		body = stream
+	}
 
 	request, err := http.NewRequestWithContext(
-		r.request.config.Context().Unwrap(),
+		// keep the path template so middlewares can label requests by route
+		httpx.WithRoute(ctx, r.request.config.Path()),
 		r.request.config.Method().String(),
 		fullURL.String(),
-		r.request.config.body.Unwrap(),
+		body,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create http request: %w", err)
 	}
 
-	// Add client cookies
+	// streamed bodies are produced again on replay, and sent chunked when
+	// their size is unknown
+	if source != nil {
+		request.GetBody = open
+		request.ContentLength = max(source.size(), 0)
+	}
+
+	if hook := r.request.config.uploadProgress; hook != nil {
+		hook.trackRequest(request)
+	}
+
+	// Add client cookies in scope
+	now := time.Now()
+
 	for _, cookie := range r.request.client.Cookies().Unwrap() {
-		request.AddCookie(cookie)
+		if cookieApplies(cookie, request.URL, now) {
+			request.AddCookie(cookie)
+		}
 	}
 
 	// Add request cookies
@@ -73,31 +126,169 @@ func (r *RequestBuilder) createHTTPRequest() (*http.Request, error) {
 	// Add client headers
 	for key, values := range *r.request.client.Header().Unwrap() {
 		for _, value := range values {
-			request.Header.Set(key, value)
+			request.Header.Add(key, value)
 		}
 	}
 
-	// Add request headers
-	for key, values := range *r.request.config.Header().Unwrap() {
-		for _, value := range values {
-			request.Header.Set(key, value)
+	// Merge request headers, which may append to, replace or remove the
+	// client ones
+	mergeHeader(request.Header, r.request.config.Header())
+
+	if compressed != nil {
+		request.Header.Set(header.ContentEncoding.String(), r.request.config.compression.String())
+	}
+
+	// Add the media types derived from the body
+	if err := r.applyMediaTypes(request); err != nil {
+		return nil, errors.Join(ErrRequestValidation, err)
+	}
+
+	// Add credentials
+	if auth := r.authenticator(); auth != nil {
+		if err := auth.Authenticate(request); err != nil {
+			return nil, errors.Join(ErrAuthenticate, err)
 		}
 	}
 
 	return request, nil
 }
 
+// applyMediaTypes sets the Content-Type and Accept derived from the body
+// according to the client MediaTypeMode.
+func (r *RequestBuilder) applyMediaTypes(request *http.Request) error {
+	mode := r.request.client.MediaTypeMode()
+	contentType := r.request.config.bodyContentType
+
+	// multipart bodies are unreadable without their boundary
+	if mode == contracts.MediaTypeOff && r.request.config.bodySource == nil {
+		return nil
+	}
+
+	if contentType != "" {
+		current := request.Header.Get(header.ContentType.String())
+
+		switch {
+		case current == "":
+			request.Header.Set(header.ContentType.String(), contentType.String())
+		case mode == contracts.MediaTypeStrict && !sameMediaType(current, contentType.String()):
+			return fmt.Errorf("%w: header is %q, body is %q", ErrMediaTypeMismatch, current, contentType)
+		}
+	}
+
+	accept := r.request.config.bodyAccept
+	if mode != contracts.MediaTypeOff && accept != "" && request.Header.Get(header.Accept.String()) == "" {
+		request.Header.Set(header.Accept.String(), accept.String())
+	}
+
+	return nil
+}
+
+// sameMediaType reports whether a and b name the same media type, ignoring
+// their parameters.
+func sameMediaType(a, b string) bool {
+	typeA, _, errA := mime.ParseMediaType(a)
+	typeB, _, errB := mime.ParseMediaType(b)
+
+	return errA == nil && errB == nil && typeA == typeB
+}
+
+// authenticator resolves the credentials of the request. Request credentials
+// take precedence over client ones.
+func (r *RequestBuilder) authenticator() contracts.Authenticator {
+	if auth := r.request.config.Authenticator(); auth != nil {
+		return auth
+	}
+
+	return r.request.client.Authenticator()
+}
+
 func (r *RequestBuilder) execute(request *http.Request) (contracts.Response, error) {
+	// bound the attempt by its own budget. The budget lasts until the
+	// response body is closed, so it must not be cancelled on return.
+	cancel := context.CancelFunc(func() {})
+	if timeout := r.request.config.AttemptTimeout(); timeout > 0 {
+		var ctx context.Context
+
+		ctx, cancel = context.WithTimeout(request.Context(), timeout)
+		request = request.WithContext(ctx)
+	}
+
 	//nolint:bodyclose // newResponse method reads response.Body, then it can not be closed here
 	response, err := r.request.client.HttpClient().Do(request)
 	if err != nil {
+		cancel()
 		return nil, fmt.Errorf("failed to execute request: %w", err)
 	}
 
-	return newResponse(response), nil
+	if response.StatusCode == http.StatusUnauthorized {
+		//nolint:bodyclose // same as above, the replayed response is handed to newResponse
+		response, err = r.reauthenticate(request, response)
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+	}
+
+	response.Body = cancelOnClose(response.Body, cancel)
+
+	if hook := r.request.config.downloadProgress; hook != nil {
+		response.Body = hook.track(response.Body, response.ContentLength)
+	}
+
+	return newResponse(response, r.codecs()), nil
+}
+
+// reauthenticate renews the credentials rejected with 401 Unauthorized and
+// replays request once. The original response is returned when the
+// authenticator cannot be refreshed or the request body cannot be replayed.
+func (r *RequestBuilder) reauthenticate(request *http.Request, response *http.Response) (*http.Response, error) {
+	auth, ok := r.authenticator().(contracts.RefreshableAuthenticator)
+	if !ok {
+		return response, nil
+	}
+
+	hasBody := request.Body != nil && request.Body != http.NoBody
+	if hasBody && request.GetBody == nil {
+		return response, nil
+	}
+
+	replay, err := auth.Refresh(request)
+	if err != nil {
+		_ = response.Body.Close()
+		return nil, errors.Join(ErrAuthenticate, err)
+	}
+
+	if !replay {
+		return response, nil
+	}
+
+	// drain to allow the connection to be reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(response.Body, 1<<20))
+	_ = response.Body.Close()
+
+	replayed := request.Clone(request.Context())
+
+	if request.GetBody != nil {
+		if replayed.Body, err = request.GetBody(); err != nil {
+			return nil, fmt.Errorf("failed to replay request body: %w", err)
+		}
+	}
+
+	if err := auth.Authenticate(replayed); err != nil {
+		return nil, errors.Join(ErrAuthenticate, err)
+	}
+
+	response, err = r.request.client.HttpClient().Do(replayed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+
+	return response, nil
 }
 
-func (r *RequestBuilder) executeWithRetry(request *http.Request) (contracts.Response, error) {
+// executeWithRetry executes request until it succeeds or runs out of
+// attempts, reporting how many were made.
+func (r *RequestBuilder) executeWithRetry(request *http.Request) (contracts.Response, int, error) {
 	config := r.request.config.RetryConfig()
 
 	//nolint:prealloc
@@ -110,6 +301,12 @@ func (r *RequestBuilder) executeWithRetry(request *http.Request) (contracts.Resp
 	const retryHeader = header.Type("X-Retry-Attempt")
 
 	for attempt := range config.MaxAttempts() {
+		if attempt > 0 {
+			if err := rewindBody(request); err != nil {
+				return nil, 0, err
+			}
+		}
+
 		request.Header.Set(retryHeader.String(), strconv.FormatUint(uint64(attempt), 36))
 
 		response, executionErr = r.execute(request)
@@ -117,11 +314,11 @@ func (r *RequestBuilder) executeWithRetry(request *http.Request) (contracts.Resp
 			shouldRetry := config.ShouldRetry()
 
 			if !shouldRetry(response) {
-				return response, nil
+				return response, int(attempt) + 1, nil
 			}
 
-			//nolint:err113
-			executionErr = errors.New(response.Status().Text())
+			// the attempt is discarded, release its connection and budget
+			executionErr = statusError(response, int(attempt)+1)
 		}
 
 		attemptsErr = append(attemptsErr, fmt.Errorf("[call %d]: %w", attempt+1, executionErr))
@@ -129,15 +326,29 @@ func (r *RequestBuilder) executeWithRetry(request *http.Request) (contracts.Resp
 		// delay before another try
 		delay := r.calculateRetryDelay(attempt)
 		if err := sleepCtx(request.Context(), delay); err != nil {
-			return nil, err
+			return nil, 0, err
 		}
 	}
 
-	return nil, fmt.Errorf(
-		"request failed after %d attempts: %w",
-		config.MaxAttempts(),
-		errors.Join(attemptsErr...),
-	)
+	return nil, 0, &retryError{attempts: config.MaxAttempts(), errs: attemptsErr}
+}
+
+// retryError reports every failed attempt of a request. It unwraps to the
+// latest attempt first, so errors.As finds how the request ended.
+type retryError struct {
+	attempts uint
+	errs     []error
+}
+
+func (e *retryError) Error() string {
+	return fmt.Sprintf("request failed after %d attempts: %s", e.attempts, errors.Join(e.errs...))
+}
+
+func (e *retryError) Unwrap() []error {
+	errs := slices.Clone(e.errs)
+	slices.Reverse(errs)
+
+	return errs
 }
 
 func (r *RequestBuilder) calculateRetryDelay(attempt uint) time.Duration {
@@ -156,28 +367,201 @@ func (r *RequestBuilder) calculateRetryDelay(attempt uint) time.Duration {
 	return time.Duration(delay)
 }
 
+// Send runs the request interceptors, sends the request unless one of them
+// short-circuits it, then runs the response interceptors.
 func (r *RequestBuilder) Send() (contracts.Response, error) {
-	req, err := r.buildRequest()
+	call := r.clone()
+
+	response, err := call.sendIntercepted()
 	if err != nil {
 		return nil, err
 	}
 
+	// interceptors may have asked for success on the copy sent
+	if call.expectsSuccess() && !response.Status().Is2xxSuccessful() {
+		return nil, statusError(response, 0)
+	}
+
+	return response, nil
+}
+
+// SendAnyStatus implements contracts.RequestBuilder.
+func (r *RequestBuilder) SendAnyStatus() (contracts.Response, error) {
+	return r.clone().sendIntercepted()
+}
+
+// sendIntercepted sends the request through the interceptors, returning
+// responses of any status. It must be called on a copy of the builder, so
+// changes the interceptors make are not seen by other sends.
+func (r *RequestBuilder) sendIntercepted() (contracts.Response, error) {
+	response, err := r.interceptRequest()
+	if err != nil {
+		return nil, err
+	}
+
+	if response == nil {
+		response, err = r.send()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return r.interceptResponse(response)
+}
+
+// Clone implements contracts.RequestBuilder.
+func (r *RequestBuilder) Clone() contracts.RequestBuilder {
+	return r.clone()
+}
+
+func (r *RequestBuilder) clone() *RequestBuilder {
+	return &RequestBuilder{
+		request: &Request{
+			client: r.request.client,
+			config: r.request.config.clone(),
+		},
+	}
+}
+
+// ExpectSuccess implements contracts.RequestBuilder.
+func (r *RequestBuilder) ExpectSuccess() contracts.RequestBuilder {
+	r.request.config.expectSuccess = true
+	return r
+}
+
+func (r *RequestBuilder) expectsSuccess() bool {
+	return r.request.config.expectSuccess || r.request.client.ExpectsSuccess()
+}
+
+// interceptRequest runs the client request interceptors, then the request
+// ones, stopping at the first one returning a response or an error.
+func (r *RequestBuilder) interceptRequest() (contracts.Response, error) {
+	interceptors := slices.Concat(
+		r.request.client.RequestInterceptors(),
+		r.request.config.Interceptors().RequestInterceptors(),
+	)
+
+	for _, intercept := range interceptors {
+		response, err := intercept(r)
+		if err != nil || response != nil {
+			return response, err
+		}
+	}
+
+	return nil, nil
+}
+
+// interceptResponse runs the request response interceptors, then the client
+// ones, both in reverse registration order. When an interceptor fails, the
+// body of the response it received is closed.
+func (r *RequestBuilder) interceptResponse(response contracts.Response) (contracts.Response, error) {
+	interceptors := slices.Concat(
+		r.request.client.ResponseInterceptors(),
+		r.request.config.Interceptors().ResponseInterceptors(),
+	)
+
+	for _, intercept := range slices.Backward(interceptors) {
+		next, err := intercept(response)
+		if err != nil {
+			response.Body().Close()
+			return nil, err
+		}
+
+		if next != nil && next != response {
+			// the body of the discarded response may never be closed
+			r.releaseBaseURL()
+			response = next
+		}
+	}
+
+	return response, nil
+}
+
+// releaseBaseURL frees the base URL of the call, once it is done with.
+func (r *RequestBuilder) releaseBaseURL() {
+	if r.release != nil {
+		r.release()
+	}
+}
+
+func (r *RequestBuilder) send() (contracts.Response, error) {
+	ctx, cancelCtx := r.callContext()
+
+	// the request is in flight on its base URL until the call completes or
+	// an interceptor replaces its response
+	baseURL, release := r.request.client.AcquireBaseURL()
+	r.release = release
+
+	cancel := func() {
+		cancelCtx()
+		release()
+	}
+
+	req, err := r.buildRequest(ctx, baseURL, true)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	var response contracts.Response
+
+	attempts := 1
+
 	retry := r.request.config.RetryConfig()
 	if retry != nil && retry.MaxAttempts() > 1 {
-		return r.executeWithRetry(req)
+		response, attempts, err = r.executeWithRetry(req)
+	} else {
+		response, err = r.execute(req)
+	}
+
+	if err != nil {
+		cancel()
+		return nil, err
 	}
 
-	return r.execute(req)
+	// the call budget covers reading the body as well
+	raw := response.Raw()
+	raw.Body = cancelOnClose(raw.Body, cancel)
+
+	result := newResponse(raw, r.codecs())
+	result.attempts = attempts
+
+	return result, nil
 }
 
 // Unwrap builds a *http.Request with all client and request configurations
 // applied. It mirrors the validations executed by Send but returns the
-// configured request instead of performing it.
+// configured request instead of performing it. Timeouts and deadlines set
+// through Context() are only enforced by Send. The base URL is chosen as for
+// Send, but the request is not counted in flight on it. Streamed bodies are
+// only opened once the request body is read.
 func (r *RequestBuilder) Unwrap() (*http.Request, error) {
-	return r.buildRequest()
+	baseURL, release := r.request.client.AcquireBaseURL()
+	release()
+
+	return r.buildRequest(r.request.config.Context().Unwrap(), baseURL, false)
+}
+
+// callContext derives the context bounding the whole call, retries and body
+// reading included, from the request context, timeout and deadline.
+func (r *RequestBuilder) callContext() (context.Context, context.CancelFunc) {
+	ctx := r.request.config.Context().Unwrap()
+	deadline := r.request.config.Deadline()
+
+	if timeout := r.request.config.Timeout(); timeout > 0 {
+		if byTimeout := time.Now().Add(timeout); deadline.IsZero() || byTimeout.Before(deadline) {
+			deadline = byTimeout
+		}
+	}
+
+	if deadline.IsZero() {
+		return ctx, func() {}
+	}
+
+	return context.WithDeadline(ctx, deadline)
 }
 
-func (r *RequestBuilder) buildRequest() (*http.Request, error) {
+func (r *RequestBuilder) buildRequest(ctx context.Context, baseURL *url.URL, send bool) (*http.Request, error) {
 	if err := errors.Join(r.request.client.Validations().Unwrap()...); err != nil {
 		return nil, errors.Join(ErrClientValidation, err)
 	}
@@ -186,7 +570,12 @@ func (r *RequestBuilder) buildRequest() (*http.Request, error) {
 		return nil, errors.Join(ErrRequestValidation, err)
 	}
 
-	req, err := r.createHTTPRequest()
+	path, err := expandPath(r.request.config.Path(), r.request.config.PathParams())
+	if err != nil {
+		return nil, errors.Join(ErrRequestValidation, err)
+	}
+
+	req, err := r.createHTTPRequest(ctx, baseURL, path, send)
 	if err != nil {
 		return nil, errors.Join(ErrCreateRequest, err)
 	}
@@ -194,6 +583,97 @@ func (r *RequestBuilder) buildRequest() (*http.Request, error) {
 	return req, nil
 }
 
+// rewindBody restores the body of request so it can be sent again.
+func rewindBody(request *http.Request) error {
+	if request.GetBody == nil {
+		return nil
+	}
+
+	body, err := request.GetBody()
+	if err != nil {
+		return fmt.Errorf("failed to replay request body: %w", err)
+	}
+
+	request.Body = body
+
+	return nil
+}
+
+// openOnRead defers open to the first Read of the body, so a request that is
+// never sent does not take contents that can only be read once.
+func openOnRead(open func() (io.ReadCloser, error)) func() (io.ReadCloser, error) {
+	return func() (io.ReadCloser, error) {
+		return &deferredBody{open: open}, nil
+	}
+}
+
+type deferredBody struct {
+	open func() (io.ReadCloser, error)
+
+	mu     sync.Mutex
+	body   io.ReadCloser
+	err    error
+	closed bool
+}
+
+func (d *deferredBody) Read(p []byte) (int, error) {
+	d.mu.Lock()
+
+	if d.closed {
+		d.mu.Unlock()
+		return 0, http.ErrBodyReadAfterClose
+	}
+
+	if d.body == nil && d.err == nil {
+		d.body, d.err = d.open()
+	}
+
+	body, err := d.body, d.err
+	d.mu.Unlock()
+
+	if err != nil {
+		return 0, err
+	}
+
+	return body.Read(p)
+}
+
+func (d *deferredBody) Close() error {
+	d.mu.Lock()
+	d.closed = true
+	body := d.body
+	d.mu.Unlock()
+
+	if body == nil {
+		return nil
+	}
+
+	return body.Close()
+}
+
+// cancelOnClose releases cancel once body is closed, keeping the context of a
+// request alive while its response body is being read.
+func cancelOnClose(body io.ReadCloser, cancel context.CancelFunc) io.ReadCloser {
+	return &cancelReadCloser{ReadCloser: body, cancel: cancel}
+}
+
+type cancelReadCloser struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c *cancelReadCloser) Close() error {
+	err := c.ReadCloser.Close()
+	c.cancel()
+
+	return err
+}
+
+// Unwrap returns the body c wraps.
+func (c *cancelReadCloser) Unwrap() io.ReadCloser {
+	return c.ReadCloser
+}
+
 func newSecureRand() *mrand.Rand {
 	var b [8]byte
 	if _, err := crand.Read(b[:]); err != nil {
@@ -0,0 +1,270 @@
+package maigo
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jeanmolossi/maigo/async"
+	"github.com/jeanmolossi/maigo/pkg/maigo/contracts"
+)
+
+func sendAndRead(t *testing.T, req contracts.RequestBuilder) (http.Header, string) {
+	t.Helper()
+
+	resp, err := req.Send()
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	body, err := resp.Body().AsString()
+	if err != nil {
+		t.Fatalf("AsString() error = %v", err)
+	}
+
+	return resp.Raw().Header, body
+}
+
+func TestRequestBuilder_CloneIsIndependent(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer(t, echo)
+
+	template := DefaultClient(server.URL).POST("/items").
+		Header().Add("X-Tag", "template").
+		Query().AddParam("page", "1").
+		Body().AsString("template body").
+		Retry().SetConstantBackoff(time.Millisecond, 2)
+
+	template.(*RequestBuilder).request.config.Cookies().Add(&http.Cookie{Name: "session", Value: "abc"})
+
+	type ctxKey struct{}
+
+	clone := template.Clone()
+	clone.(*RequestBuilder).request.config.Cookies().Add(&http.Cookie{Name: "tenant", Value: "42"})
+
+	clone = clone.
+		Header().Set("X-Tag", "clone").
+		Query().AddParam("page", "2").
+		Body().AsString("clone body").
+		Retry().SetConstantBackoff(time.Millisecond, 5).
+		Context().Set(context.WithValue(context.Background(), ctxKey{}, "clone"))
+
+	cloneHeader, cloneBody := sendAndRead(t, clone)
+	templateHeader, templateBody := sendAndRead(t, template)
+
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{name: "template query", got: templateHeader.Get("X-Query"), want: "page=1"},
+		{name: "template tags", got: templateHeader.Get("X-Tags"), want: "template"},
+		{name: "template cookies", got: templateHeader.Get("X-Cookies"), want: "session=abc"},
+		{name: "template body", got: templateBody, want: "template body"},
+		{name: "clone query", got: cloneHeader.Get("X-Query"), want: "page=1&page=2"},
+		{name: "clone tags", got: cloneHeader.Get("X-Tags"), want: "clone"},
+		{name: "clone cookies", got: cloneHeader.Get("X-Cookies"), want: "session=abc,tenant=42"},
+		{name: "clone body", got: cloneBody, want: "clone body"},
+	}
+
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s = %q, want %q", tt.name, tt.got, tt.want)
+		}
+	}
+
+	config := template.(*RequestBuilder).request.config
+	if attempts := config.RetryConfig().MaxAttempts(); attempts != 2 {
+		t.Errorf("template MaxAttempts() = %d, want 2", attempts)
+	}
+
+	if value := config.Context().Unwrap().Value(ctxKey{}); value != nil {
+		t.Errorf("template context value = %v, want nil", value)
+	}
+}
+
+func TestRequestBuilder_SendIsRepeatable(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer(t, echo)
+
+	template := DefaultClient(server.URL).PUT("/items").
+		Body().AsJSON(map[string]int{"id": 1}).
+		Intercept().OnRequest(func(req contracts.RequestBuilder) (contracts.Response, error) {
+		// changes made by interceptors last for a single send
+		req.Header().Add("X-Tag", "intercepted")
+		return nil, nil
+	})
+
+	for range 2 {
+		header, body := sendAndRead(t, template)
+
+		if got := header.Get("X-Tags"); got != "intercepted" {
+			t.Fatalf("X-Tags = %q, want %q", got, "intercepted")
+		}
+
+		if body != "{\"id\":1}\n" {
+			t.Fatalf("body = %q, want the JSON body", body)
+		}
+	}
+
+	builders := make([]contracts.RequestBuilder, 16)
+	for i := range builders {
+		builders[i] = template
+	}
+
+	group, err := async.All(4, builders...)
+	if err != nil {
+		t.Fatalf("All() error = %v", err)
+	}
+
+	group.Wait()
+
+	for i := range builders {
+		resp, err := group.Result(i)
+		if err != nil {
+			t.Fatalf("Result(%d) error = %v", i, err)
+		}
+
+		body, _ := resp.Body().AsString()
+		if got := resp.Raw().Header.Get("X-Tags"); got != "intercepted" || body != "{\"id\":1}\n" {
+			t.Fatalf("Result(%d) = %q, %q", i, got, body)
+		}
+	}
+}
+
+func TestRequestBuilder_SendMultipartConcurrently(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer(t, echo)
+
+	content := bytes.Repeat([]byte("mai"), 64<<10)
+
+	template := DefaultClient(server.URL).POST("/upload").
+		Body().AsMultipart().
+		File("file", "mai.txt", bytes.NewReader(content)).
+		Done()
+
+	builders := make([]contracts.RequestBuilder, 8)
+	for i := range builders {
+		builders[i] = template
+	}
+
+	group, err := async.All(0, builders...)
+	if err != nil {
+		t.Fatalf("All() error = %v", err)
+	}
+
+	group.Wait()
+
+	for i := range builders {
+		resp, err := group.Result(i)
+		if err != nil {
+			t.Fatalf("Result(%d) error = %v", i, err)
+		}
+
+		body, _ := resp.Body().AsBytes()
+		if !bytes.Contains(body, content) {
+			t.Fatalf("Result(%d) body does not hold the whole file", i)
+		}
+	}
+}
+
+func TestRequestBuilder_OneShotMultipartIsNotResent(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer(t, respond(http.StatusOK, ""))
+
+	template := DefaultClient(server.URL).POST("/upload").
+		Body().AsMultipart().
+		File("log", "app.log", io.MultiReader(strings.NewReader("once"))).
+		Done()
+
+	if _, err := template.Send(); err != nil {
+		t.Fatalf("first Send() error = %v", err)
+	}
+
+	for _, req := range []contracts.RequestBuilder{template, template.Clone()} {
+		if _, err := req.Send(); !errors.Is(err, ErrBodyNotReplayable) {
+			t.Fatalf("Send() error = %v, want %v", err, ErrBodyNotReplayable)
+		}
+	}
+
+	// the failed sends never reach the server with a truncated part
+	if got := server.Hits(); got != 1 {
+		t.Fatalf("requests = %d, want 1", got)
+	}
+}
+
+// gatedReader is a reader with Read and Seek only, blocking its first Read
+// until gate is closed.
+type gatedReader struct {
+	io.ReadSeeker
+
+	read    atomic.Bool
+	entered chan struct{}
+	gate    chan struct{}
+}
+
+func (g *gatedReader) Read(p []byte) (int, error) {
+	if !g.read.Swap(true) {
+		close(g.entered)
+		<-g.gate
+	}
+
+	return g.ReadSeeker.Read(p)
+}
+
+func TestRequestBuilder_SeekableMultipartClonesDoNotShareReader(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer(t, echo)
+
+	content := bytes.Repeat([]byte("mai"), 64<<10)
+	reader := &gatedReader{
+		ReadSeeker: bytes.NewReader(content),
+		entered:    make(chan struct{}),
+		gate:       make(chan struct{}),
+	}
+
+	template := DefaultClient(server.URL).POST("/upload").
+		Body().AsMultipart().
+		File("file", "mai.txt", reader).
+		Done()
+
+	sent := make(chan error, 1)
+
+	go func() {
+		resp, err := template.Clone().Send()
+		if err == nil {
+			_, err = resp.Body().AsString()
+		}
+
+		sent <- err
+	}()
+
+	<-reader.entered
+
+	// the reader offset belongs to the send in progress
+	if _, err := template.Clone().Send(); !errors.Is(err, ErrBodyNotReplayable) {
+		t.Fatalf("concurrent Send() error = %v, want %v", err, ErrBodyNotReplayable)
+	}
+
+	close(reader.gate)
+
+	if err := <-sent; err != nil {
+		t.Fatalf("first Send() error = %v", err)
+	}
+
+	// once the first one is done, a clone sends the whole file again
+	if _, body := sendAndRead(t, template.Clone()); !strings.Contains(body, string(content)) {
+		t.Fatal("Send() after the first one does not hold the whole file")
+	}
+}
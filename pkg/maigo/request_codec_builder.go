@@ -0,0 +1,34 @@
+package maigo
+
+import (
+	"fmt"
+
+	"github.com/jeanmolossi/maigo/pkg/maigo/contracts"
+	"github.com/jeanmolossi/maigo/pkg/maigo/mime"
+)
+
+var _ contracts.BuilderCodec[contracts.RequestBuilder] = (*RequestCodecBuilder)(nil)
+
+type RequestCodecBuilder struct {
+	parent *RequestBuilder
+	config *RequestConfigBase
+}
+
+func (r *RequestBuilder) Codec() contracts.BuilderCodec[contracts.RequestBuilder] {
+	return &RequestCodecBuilder{
+		parent: r,
+		config: r.request.config,
+	}
+}
+
+// Register implements contracts.BuilderCodec.
+func (r *RequestCodecBuilder) Register(mediaType mime.Type, codec contracts.Codec) contracts.RequestBuilder {
+	if codec == nil {
+		r.config.validations.Add(fmt.Errorf("%w: nil codec for %s", ErrInvalidCodec, mediaType))
+		return r.parent
+	}
+
+	r.config.codecs.Register(mediaType, codec)
+
+	return r.parent
+}
@@ -1,11 +1,16 @@
 package maigo
 
 import (
+	"maps"
 	"net/url"
+	"slices"
 	"time"
 
+	"github.com/jeanmolossi/maigo/pkg/httpx/compression"
+	"github.com/jeanmolossi/maigo/pkg/maigo/codec"
 	"github.com/jeanmolossi/maigo/pkg/maigo/contracts"
 	"github.com/jeanmolossi/maigo/pkg/maigo/method"
+	"github.com/jeanmolossi/maigo/pkg/maigo/mime"
 )
 
 type (
@@ -13,12 +18,38 @@ type (
 		ctx          contracts.Context
 		httpHeader   contracts.Header
 		httpCookies  contracts.Cookies
+		auth         contracts.Authenticator
+		interceptors contracts.ConfigInterceptors
+		codecs       contracts.Codecs
 		method       method.Type
 		path         string
+		pathParams   map[string]string
 		searchParams url.Values
 		body         contracts.Body
 		validations  contracts.Validations
 		retryConfig  *RetryConfig
+
+		// bodyContentType and bodyAccept are the media types derived from
+		// the body, applied according to the client MediaTypeMode.
+		bodyContentType mime.Type
+		bodyAccept      mime.Type
+		// bodySource streams the body in place of body when set.
+		bodySource bodySource
+		// compression is the content coding applied to bodies of at least
+		// compressionMinSize bytes.
+		compression        compression.Encoding
+		compressionMinSize int64
+		// uploadProgress and downloadProgress observe the request and
+		// response bodies.
+		uploadProgress   *progressHook
+		downloadProgress *progressHook
+
+		timeout        time.Duration
+		deadline       time.Time
+		attemptTimeout time.Duration
+
+		// expectSuccess reports non-2xx responses as errors.
+		expectSuccess bool
 	}
 
 	JitterStrategy string
@@ -52,6 +83,22 @@ func (r *RequestConfigBase) Cookies() contracts.Cookies {
 	return r.httpCookies
 }
 
+// Authenticator returns the request credentials or nil when the client ones
+// should be used.
+func (r *RequestConfigBase) Authenticator() contracts.Authenticator {
+	return r.auth
+}
+
+// Interceptors returns the interceptors registered on the request only.
+func (r *RequestConfigBase) Interceptors() contracts.ConfigInterceptors {
+	return r.interceptors
+}
+
+// Codecs returns the codecs registered on the request only.
+func (r *RequestConfigBase) Codecs() contracts.Codecs {
+	return r.codecs
+}
+
 func (r *RequestConfigBase) Method() method.Type {
 	return r.method
 }
@@ -60,6 +107,11 @@ func (r *RequestConfigBase) Path() string {
 	return r.path
 }
 
+// PathParams returns the values of the path template placeholders.
+func (r *RequestConfigBase) PathParams() map[string]string {
+	return r.pathParams
+}
+
 func (r *RequestConfigBase) SearchParams() url.Values {
 	return r.searchParams
 }
@@ -68,10 +120,72 @@ func (r *RequestConfigBase) RetryConfig() *RetryConfig {
 	return r.retryConfig
 }
 
+// Timeout returns the budget of the whole call or zero when unbounded.
+func (r *RequestConfigBase) Timeout() time.Duration {
+	return r.timeout
+}
+
+// Deadline returns the absolute deadline of the whole call or the zero time
+// when unbounded.
+func (r *RequestConfigBase) Deadline() time.Time {
+	return r.deadline
+}
+
+// AttemptTimeout returns the budget of each attempt or zero when unbounded.
+func (r *RequestConfigBase) AttemptTimeout() time.Duration {
+	return r.attemptTimeout
+}
+
+// setBody records the media types and the source of the body last set.
+func (r *RequestConfigBase) setBody(contentType, accept mime.Type, source bodySource) {
+	r.bodyContentType = contentType
+	r.bodyAccept = accept
+	r.bodySource = source
+}
+
 func (r *RequestConfigBase) Validations() contracts.Validations {
 	return r.validations
 }
 
+// clone returns a deep copy of the configuration. Body sources,
+// authenticators, interceptors and progress callbacks are shared, as they
+// are not changed once set; a multipart part read from a one-shot reader is
+// only sent by the first of the copies.
+func (r *RequestConfigBase) clone() *RequestConfigBase {
+	clone := *r
+
+	clone.ctx = &Context{ctx: r.ctx.Unwrap()}
+	clone.httpHeader = cloneHeader(r.httpHeader)
+	clone.httpCookies = &Cookies{cookies: r.httpCookies.Unwrap()}
+	clone.pathParams = maps.Clone(r.pathParams)
+	clone.validations = newDefaultValidations(slices.Clone(r.validations.Unwrap()))
+
+	clone.searchParams = make(url.Values, len(r.searchParams))
+	for param, values := range r.searchParams {
+		clone.searchParams[param] = slices.Clone(values)
+	}
+
+	clone.interceptors = &Interceptors{
+		request:  slices.Clone(r.interceptors.RequestInterceptors()),
+		response: slices.Clone(r.interceptors.ResponseInterceptors()),
+	}
+
+	if registry, ok := r.codecs.(*codec.Registry); ok {
+		clone.codecs = registry.Clone()
+	}
+
+	if body, ok := r.body.(*BufferedBody); ok {
+		clone.body = body.clone()
+	}
+
+	if r.retryConfig != nil {
+		retryConfig := *r.retryConfig
+		clone.retryConfig = &retryConfig
+	}
+
+	return &clone
+}
+
 // RetryConfig methods
 
 func (r *RetryConfig) ShouldRetry() func(response contracts.Response) bool {
@@ -129,9 +243,12 @@ func newRequestConfigBase(method method.Type, path string) *RequestConfigBase {
 		httpCookies:  newDefaultHTTPCookies(),
 		method:       method,
 		path:         path,
+		pathParams:   map[string]string{},
 		searchParams: url.Values{},
 		body:         newBufferedBody(),
 		validations:  newDefaultValidations(nil),
+		interceptors: newDefaultInterceptors(),
+		codecs:       codec.NewRegistry(),
 		retryConfig: &RetryConfig{
 			shouldRetry: func(response contracts.Response) bool {
 				return response.Status().IsError()
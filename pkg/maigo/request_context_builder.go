@@ -2,6 +2,8 @@ package maigo
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/jeanmolossi/maigo/pkg/maigo/contracts"
 )
@@ -25,3 +27,33 @@ func (r *RequestContextBuilder) Set(ctx context.Context) contracts.RequestBuilde
 	r.config.Context().Set(ctx)
 	return r.parent
 }
+
+// SetTimeout implements contracts.BuilderRequestContext.
+func (r *RequestContextBuilder) SetTimeout(d time.Duration) contracts.RequestBuilder {
+	if d < 0 {
+		r.config.validations.Add(fmt.Errorf("%w: negative timeout %s", ErrInvalidTimeout, d))
+		return r.parent
+	}
+
+	r.config.timeout = d
+
+	return r.parent
+}
+
+// SetDeadline implements contracts.BuilderRequestContext.
+func (r *RequestContextBuilder) SetDeadline(t time.Time) contracts.RequestBuilder {
+	r.config.deadline = t
+	return r.parent
+}
+
+// SetAttemptTimeout implements contracts.BuilderRequestContext.
+func (r *RequestContextBuilder) SetAttemptTimeout(d time.Duration) contracts.RequestBuilder {
+	if d < 0 {
+		r.config.validations.Add(fmt.Errorf("%w: negative attempt timeout %s", ErrInvalidTimeout, d))
+		return r.parent
+	}
+
+	r.config.attemptTimeout = d
+
+	return r.parent
+}
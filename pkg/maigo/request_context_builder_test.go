@@ -0,0 +1,122 @@
+package maigo
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRequestContext_TimeoutExceeded(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	_, err := DefaultClient(server.URL).
+		GET("/").
+		Context().SetTimeout(20 * time.Millisecond).
+		Send()
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Send() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestRequestContext_DeadlineComposesWithContext(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := DefaultClient("https://example.com").
+		GET("/").
+		Context().Set(ctx).
+		Context().SetDeadline(time.Now().Add(time.Hour)).
+		Send()
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Send() error = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestRequestContext_BodyReadableAfterSend(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, "hello")
+	}))
+	t.Cleanup(server.Close)
+
+	resp, err := DefaultClient(server.URL).
+		GET("/").
+		Context().SetTimeout(time.Second).
+		Context().SetAttemptTimeout(time.Second).
+		Send()
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	body, err := resp.Body().AsString()
+	if err != nil || body != "hello" {
+		t.Fatalf("AsString() = %q, %v, want %q", body, err, "hello")
+	}
+}
+
+func TestRequestContext_AttemptTimeoutRetries(t *testing.T) {
+	t.Parallel()
+
+	var hits atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		// the first attempt hangs until the client gives up on it
+		if hits.Add(1) == 1 {
+			<-r.Context().Done()
+			return
+		}
+
+		_, _ = w.Write(body)
+	}))
+	t.Cleanup(server.Close)
+
+	resp, err := DefaultClient(server.URL).
+		POST("/").
+		Body().AsString("payload").
+		Context().SetTimeout(time.Second).
+		Context().SetAttemptTimeout(50*time.Millisecond).
+		Retry().SetConstantBackoff(time.Millisecond, 2).
+		Send()
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	body, err := resp.Body().AsString()
+	if err != nil || body != "payload" {
+		t.Fatalf("AsString() = %q, %v, want %q", body, err, "payload")
+	}
+
+	if got := hits.Load(); got != 2 {
+		t.Fatalf("server hits = %d, want 2", got)
+	}
+}
+
+func TestRequestContext_NegativeTimeout(t *testing.T) {
+	t.Parallel()
+
+	_, err := DefaultClient("https://example.com").
+		GET("/").
+		Context().SetAttemptTimeout(-time.Second).
+		Unwrap()
+	if !errors.Is(err, ErrInvalidTimeout) {
+		t.Fatalf("Unwrap() error = %v, want %v", err, ErrInvalidTimeout)
+	}
+}
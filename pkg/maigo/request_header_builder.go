@@ -67,3 +67,15 @@ func (r *RequestHeaderBuilder) SetAll(headers map[header.Type]string) contracts.
 
 	return r.parent
 }
+
+// Append implements contracts.BuilderHeader.
+func (r *RequestHeaderBuilder) Append(key header.Type, value string) contracts.RequestBuilder {
+	appendHeader(r.config.httpHeader, key, value)
+	return r.parent
+}
+
+// Remove implements contracts.BuilderHeader.
+func (r *RequestHeaderBuilder) Remove(key header.Type) contracts.RequestBuilder {
+	removeHeader(r.config.httpHeader, key)
+	return r.parent
+}
@@ -0,0 +1,45 @@
+package maigo
+
+import (
+	"fmt"
+
+	"github.com/jeanmolossi/maigo/pkg/maigo/contracts"
+)
+
+var _ contracts.BuilderInterceptor[contracts.RequestBuilder] = (*RequestInterceptBuilder)(nil)
+
+type RequestInterceptBuilder struct {
+	parent *RequestBuilder
+	config *RequestConfigBase
+}
+
+func (r *RequestBuilder) Intercept() contracts.BuilderInterceptor[contracts.RequestBuilder] {
+	return &RequestInterceptBuilder{
+		parent: r,
+		config: r.request.config,
+	}
+}
+
+// OnRequest implements contracts.BuilderInterceptor.
+func (r *RequestInterceptBuilder) OnRequest(interceptor contracts.RequestInterceptor) contracts.RequestBuilder {
+	if interceptor == nil {
+		r.config.validations.Add(fmt.Errorf("%w: nil request interceptor", ErrInvalidInterceptor))
+		return r.parent
+	}
+
+	r.config.interceptors.AddRequestInterceptor(interceptor)
+
+	return r.parent
+}
+
+// OnResponse implements contracts.BuilderInterceptor.
+func (r *RequestInterceptBuilder) OnResponse(interceptor contracts.ResponseInterceptor) contracts.RequestBuilder {
+	if interceptor == nil {
+		r.config.validations.Add(fmt.Errorf("%w: nil response interceptor", ErrInvalidInterceptor))
+		return r.parent
+	}
+
+	r.config.interceptors.AddResponseInterceptor(interceptor)
+
+	return r.parent
+}
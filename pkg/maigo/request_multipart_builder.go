@@ -0,0 +1,85 @@
+package maigo
+
+import (
+	"errors"
+	"io"
+	"net/textproto"
+
+	"github.com/jeanmolossi/maigo/pkg/maigo/contracts"
+	"github.com/jeanmolossi/maigo/pkg/maigo/mime"
+)
+
+var _ contracts.BuilderRequestMultipart[contracts.RequestBuilder] = (*RequestMultipartBuilder)(nil)
+
+type RequestMultipartBuilder struct {
+	parent *RequestBuilder
+	config *RequestConfigBase
+	body   *multipartBody
+}
+
+// AsMultipart implements contracts.BuilderRequestBody.
+func (r *RequestBodyBuilder) AsMultipart() contracts.BuilderRequestMultipart[contracts.RequestBuilder] {
+	body := newMultipartBody()
+
+	r.config.setBody(mime.Type(body.contentType()), "", body)
+
+	return &RequestMultipartBuilder{
+		parent: r.parent,
+		config: r.config,
+		body:   body,
+	}
+}
+
+// Field implements contracts.BuilderRequestMultipart.
+func (r *RequestMultipartBuilder) Field(name, value string) contracts.BuilderRequestMultipart[contracts.RequestBuilder] {
+	r.body.add(newFieldPart(name, value))
+	return r
+}
+
+// File implements contracts.BuilderRequestMultipart.
+func (r *RequestMultipartBuilder) File(
+	field, filename string,
+	content io.Reader,
+) contracts.BuilderRequestMultipart[contracts.RequestBuilder] {
+	if content == nil {
+		r.config.validations.Add(errors.Join(ErrToSetBody, errors.New("nil file content")))
+		return r
+	}
+
+	r.body.add(newFilePart(field, filename, content))
+
+	return r
+}
+
+// FilePath implements contracts.BuilderRequestMultipart.
+func (r *RequestMultipartBuilder) FilePath(field, path string) contracts.BuilderRequestMultipart[contracts.RequestBuilder] {
+	part, err := newPathPart(field, path)
+	if err != nil {
+		r.config.validations.Add(errors.Join(ErrToSetBody, err))
+		return r
+	}
+
+	r.body.add(part)
+
+	return r
+}
+
+// Part implements contracts.BuilderRequestMultipart.
+func (r *RequestMultipartBuilder) Part(
+	header textproto.MIMEHeader,
+	content io.Reader,
+) contracts.BuilderRequestMultipart[contracts.RequestBuilder] {
+	if content == nil {
+		r.config.validations.Add(errors.Join(ErrToSetBody, errors.New("nil part content")))
+		return r
+	}
+
+	r.body.add(newReaderPart(header, content))
+
+	return r
+}
+
+// Done implements contracts.BuilderRequestMultipart.
+func (r *RequestMultipartBuilder) Done() contracts.RequestBuilder {
+	return r.parent
+}
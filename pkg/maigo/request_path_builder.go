@@ -0,0 +1,32 @@
+package maigo
+
+import "github.com/jeanmolossi/maigo/pkg/maigo/contracts"
+
+var _ contracts.BuilderRequestPath[contracts.RequestBuilder] = (*RequestPathBuilder)(nil)
+
+type RequestPathBuilder struct {
+	parent *RequestBuilder
+	config *RequestConfigBase
+}
+
+func (r *RequestBuilder) Path() contracts.BuilderRequestPath[contracts.RequestBuilder] {
+	return &RequestPathBuilder{
+		parent: r,
+		config: r.request.config,
+	}
+}
+
+// Param implements contracts.BuilderRequestPath.
+func (r *RequestPathBuilder) Param(name, value string) contracts.RequestBuilder {
+	r.config.pathParams[name] = value
+	return r.parent
+}
+
+// Params implements contracts.BuilderRequestPath.
+func (r *RequestPathBuilder) Params(params contracts.Params) contracts.RequestBuilder {
+	for name, value := range params {
+		r.Param(name, value)
+	}
+
+	return r.parent
+}
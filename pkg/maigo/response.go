@@ -3,13 +3,17 @@ package maigo
 import (
 	"net/http"
 
+	"github.com/jeanmolossi/maigo/pkg/httpx/compression"
 	"github.com/jeanmolossi/maigo/pkg/maigo/contracts"
+	"github.com/jeanmolossi/maigo/pkg/maigo/header"
 )
 
 var _ contracts.Response = (*Response)(nil)
 
 type Response struct {
 	raw *http.Response
+	// attempts is the number of attempts made to obtain raw.
+	attempts int
 
 	// Fluent API
 	body    contracts.ResponseFluentBody
@@ -48,15 +52,37 @@ func (r *Response) Raw() *http.Response {
 	return r.raw
 }
 
-func newResponse(response *http.Response) *Response {
+// NewResponse wraps response in the fluent contracts.Response. It is meant for
+// interceptors and tests building synthetic responses; a nil Body or Header
+// is treated as empty. Its body is decoded with the built-in codecs.
+func NewResponse(response *http.Response) contracts.Response {
+	if response.Body == nil {
+		response.Body = http.NoBody
+	}
+
+	if response.Header == nil {
+		response.Header = make(http.Header)
+	}
+
+	return newResponse(response, builtinCodecs)
+}
+
+func newResponse(response *http.Response, codecs contracts.Codecs) *Response {
 	return &Response{
 		raw: response,
 		// Fluent API
 		body: &ResponseBody{
-			body: newUnbufferedBody(response.Body),
+			body:            newUnbufferedBody(response.Body),
+			contentType:     response.Header.Get(header.ContentType.String()),
+			codecs:          codecs,
+			contentEncoding: originalEncoding(response),
+			header:          response.Header,
+			partial:         response.StatusCode == http.StatusPartialContent,
+			size:            response.ContentLength,
 		},
 		cookie: &ResponseCookie{
 			cookies: response.Cookies(),
+			host:    responseHost(response),
 		},
 		header: &ResponseHeader{
 			header: response.Header,
@@ -69,3 +95,27 @@ func newResponse(response *http.Response) *Response {
 		},
 	}
 }
+
+// responseHost returns the host of the request response answers, if known.
+func responseHost(response *http.Response) string {
+	if response.Request == nil || response.Request.URL == nil {
+		return ""
+	}
+
+	return response.Request.URL.Hostname()
+}
+
+// originalEncoding returns the Content-Encoding response was sent with,
+// including when a middleware or the transport decompressed it.
+func originalEncoding(response *http.Response) string {
+	if encoding := compression.OriginalEncoding(response); encoding != "" {
+		return encoding
+	}
+
+	// the transport only decompresses the gzip it asked for
+	if response.Uncompressed {
+		return compression.Gzip.String()
+	}
+
+	return response.Header.Get(header.ContentEncoding.String())
+}
@@ -4,16 +4,36 @@ import (
 	"bytes"
 	"encoding/json"
 	"encoding/xml"
+	"errors"
 	"fmt"
 	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
 
 	"github.com/jeanmolossi/maigo/pkg/maigo/contracts"
+	"github.com/jeanmolossi/maigo/pkg/maigo/digest"
+	"github.com/jeanmolossi/maigo/pkg/maigo/header"
+	"github.com/jeanmolossi/maigo/pkg/maigo/mime"
 )
 
 var _ contracts.ResponseFluentBody = (*ResponseBody)(nil)
 
 type ResponseBody struct {
 	body contracts.Body
+	// contentType selects the codec used by Decode.
+	contentType string
+	codecs      contracts.Codecs
+	// contentEncoding is the encoding the body was sent with.
+	contentEncoding string
+	// header holds the digests announced by the server.
+	header http.Header
+	// partial is set when the body is a range of the representation.
+	partial bool
+	// size is the length of the body, or -1 when unknown.
+	size int64
 }
 
 // AsBytes implements contracts.ResponseFluentBody.
@@ -47,7 +67,7 @@ func AsJSON[T any](r *ResponseBody) (T, error) {
 	defer r.Close()
 
 	var v T
-	if err := r.body.ReadAsJSON(&v); err != nil {
+	if err := r.decodeAs(mime.JSON, &v); err != nil {
 		var zero T
 		return zero, fmt.Errorf("failed reading as JSON: %w", err)
 	}
@@ -78,7 +98,7 @@ func AsXML[T any](r *ResponseBody) (T, error) {
 	defer r.Close()
 
 	var v T
-	if err := r.body.ReadAsXML(&v); err != nil {
+	if err := r.decodeAs(mime.XML, &v); err != nil {
 		var zero T
 		return zero, fmt.Errorf("failed reading as XML: %w", err)
 	}
@@ -86,6 +106,126 @@ func AsXML[T any](r *ResponseBody) (T, error) {
 	return v, nil
 }
 
+// Decode implements contracts.ResponseFluentBody.
+func (r *ResponseBody) Decode(v any) error {
+	if r.contentType == "" {
+		return fmt.Errorf("%w: response has no Content-Type", ErrUnsupportedMediaType)
+	}
+
+	return r.decodeAs(mime.Type(r.contentType), v)
+}
+
+// decodeAs closes the body once it is decoded into v by the codec of
+// mediaType.
+func (r *ResponseBody) decodeAs(mediaType mime.Type, v any) error {
+	defer r.Close()
+
+	codecs := r.codecs
+	if codecs == nil {
+		codecs = builtinCodecs
+	}
+
+	codec, ok := codecs.Lookup(mediaType)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnsupportedMediaType, mediaType)
+	}
+
+	if err := codec.Decode(r.body, v); err != nil {
+		return errors.Join(ErrToDecodeBody, err)
+	}
+
+	return nil
+}
+
+// OnProgress implements contracts.ResponseFluentBody.
+func (r *ResponseBody) OnProgress(fn contracts.ProgressFunc, interval time.Duration) contracts.ResponseFluentBody {
+	if hook := newProgressHook(fn, interval); hook != nil {
+		r.body = newUnbufferedBody(hook.track(r.body, r.size))
+	}
+
+	return r
+}
+
+// ToWriter implements contracts.ResponseFluentBody.
+func (r *ResponseBody) ToWriter(w io.Writer, checksums ...digest.Checksum) (int64, error) {
+	defer r.Close()
+
+	verifier, err := digest.NewVerifier(slices.Concat(checksums, r.digests(false))...)
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := io.Copy(io.MultiWriter(w, verifier), r.body)
+	if err != nil {
+		return n, fmt.Errorf("failed streaming body: %w", err)
+	}
+
+	return n, verifier.Verify()
+}
+
+// ToFile implements contracts.ResponseFluentBody.
+func (r *ResponseBody) ToFile(path string, checksums ...digest.Checksum) error {
+	file, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*"+partSuffix)
+	if err != nil {
+		r.Close()
+		return fmt.Errorf("%w: %w", ErrToWriteFile, err)
+	}
+
+	if _, err := r.ToWriter(file, checksums...); err != nil {
+		_ = file.Close()
+		_ = os.Remove(file.Name())
+
+		return err
+	}
+
+	if err := file.Chmod(filePerm); err != nil {
+		_ = file.Close()
+		_ = os.Remove(file.Name())
+
+		return fmt.Errorf("%w: %w", ErrToWriteFile, err)
+	}
+
+	if err := commitFile(file, path); err != nil {
+		_ = os.Remove(file.Name())
+		return err
+	}
+
+	return nil
+}
+
+// digests returns the checksums announced by the server. The content
+// digests cover the bytes of the body while the representation digests
+// cover the whole resource, so for a range only the ones matching what is
+// checked apply: the body, or the whole resource when whole is set.
+func (r *ResponseBody) digests(whole bool) []digest.Checksum {
+	// the digests describe the encoded bytes, not the decompressed ones
+	if r.header == nil || r.contentEncoding != "" && r.header.Get(header.ContentEncoding.String()) == "" {
+		return nil
+	}
+
+	var checksums []digest.Checksum
+
+	if !whole || !r.partial {
+		checksums = append(checksums, digest.ContentDigests(r.header)...)
+	}
+
+	if whole || !r.partial {
+		checksums = append(checksums, digest.ReprDigests(r.header)...)
+	}
+
+	return checksums
+}
+
+// IsProblem implements contracts.ResponseFluentBody.
+func (r *ResponseBody) IsProblem() bool {
+	return isProblemType(r.contentType)
+}
+
+// ContentEncoding implements contracts.ResponseFluentBody.
+func (r *ResponseBody) ContentEncoding() string {
+	return r.contentEncoding
+}
+
 // Close implements contracts.ResponseFluentBody.
 func (r *ResponseBody) Close() {
 	_ = r.body.Close()
@@ -1,7 +1,9 @@
 package maigo
 
 import (
+	"net"
 	"net/http"
+	"strings"
 
 	"github.com/jeanmolossi/maigo/pkg/maigo/contracts"
 )
@@ -10,9 +12,55 @@ var _ contracts.ResponseFluentCookie = (*ResponseCookie)(nil)
 
 type ResponseCookie struct {
 	cookies []*http.Cookie
+	// host is the host that answered, owning the cookies without Domain.
+	host string
 }
 
 // GetAll implements contracts.ResponseFluentCookie.
 func (r *ResponseCookie) GetAll() []*http.Cookie {
 	return r.cookies
 }
+
+// Get implements contracts.ResponseFluentCookie.
+func (r *ResponseCookie) Get(name string) *http.Cookie {
+	for _, cookie := range r.cookies {
+		if cookie.Name == name {
+			return cookie
+		}
+	}
+
+	return nil
+}
+
+// GetByDomain implements contracts.ResponseFluentCookie.
+func (r *ResponseCookie) GetByDomain(domain string) []*http.Cookie {
+	domain = strings.ToLower(strings.TrimSuffix(domain, "."))
+
+	var cookies []*http.Cookie
+
+	for _, cookie := range r.cookies {
+		if cookie.Domain == "" {
+			if strings.EqualFold(r.host, domain) {
+				cookies = append(cookies, cookie)
+			}
+
+			continue
+		}
+
+		if domainMatch(domain, strings.ToLower(strings.TrimPrefix(cookie.Domain, "."))) {
+			cookies = append(cookies, cookie)
+		}
+	}
+
+	return cookies
+}
+
+// domainMatch reports whether domain is cookieDomain or one of its
+// subdomains, as defined by RFC 6265 section 5.1.3.
+func domainMatch(domain, cookieDomain string) bool {
+	if domain == cookieDomain {
+		return true
+	}
+
+	return net.ParseIP(domain) == nil && strings.HasSuffix(domain, "."+cookieDomain)
+}
@@ -0,0 +1,152 @@
+package maigo
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"strings"
+
+	"github.com/jeanmolossi/maigo/pkg/maigo/contracts"
+	"github.com/jeanmolossi/maigo/pkg/maigo/header"
+	"github.com/jeanmolossi/maigo/pkg/maigo/mime"
+)
+
+// StreamJSON decodes the response body one element at a time, so large
+// payloads are read with bounded memory. The body may either be a top-level
+// JSON array, yielding its elements, or newline-delimited JSON, yielding each
+// value. Bodies sent as application/x-ndjson or application/jsonl are always
+// read as newline-delimited JSON, so their values may be arrays themselves;
+// other bodies are read as an array when they start with '['. The body is
+// closed once the iteration ends, including when the loop is left early,
+// and the iteration stops after the first error.
+//
+// Example:
+//
+//	for user, err := range maigo.StreamJSON[User](resp) {
+//	    if err != nil {
+//	        return err
+//	    }
+//	    ...
+//	}
+func StreamJSON[T any](resp contracts.Response) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		defer resp.Body().Close()
+
+		reader := bufio.NewReader(responseReader(resp))
+
+		first, err := peekNonSpace(reader)
+		if errors.Is(err, io.EOF) {
+			return
+		}
+
+		if err != nil {
+			var zero T
+
+			yield(zero, fmt.Errorf("%w: %w", ErrToDecodeBody, err))
+
+			return
+		}
+
+		decoder := json.NewDecoder(reader)
+
+		if first == '[' && !isNDJSONType(resp.Header().Get(header.ContentType.String())) {
+			streamJSONArray(decoder, yield)
+			return
+		}
+
+		streamJSONValues(decoder, yield)
+	}
+}
+
+// streamJSONArray yields the elements of the array read by decoder.
+func streamJSONArray[T any](decoder *json.Decoder, yield func(T, error) bool) {
+	var zero T
+
+	// consume the opening bracket
+	if _, err := decoder.Token(); err != nil {
+		yield(zero, fmt.Errorf("%w: %w", ErrToDecodeBody, err))
+		return
+	}
+
+	for index := 0; decoder.More(); index++ {
+		var v T
+		if err := decoder.Decode(&v); err != nil {
+			yield(zero, fmt.Errorf("%w: element %d: %w", ErrToDecodeBody, index, err))
+			return
+		}
+
+		if !yield(v, nil) {
+			return
+		}
+	}
+
+	// a truncated array is reported instead of silently ending
+	if _, err := decoder.Token(); err != nil {
+		yield(zero, fmt.Errorf("%w: %w", ErrToDecodeBody, err))
+	}
+}
+
+// streamJSONValues yields the whitespace separated values read by decoder,
+// as in newline-delimited JSON.
+func streamJSONValues[T any](decoder *json.Decoder, yield func(T, error) bool) {
+	for index := 0; ; index++ {
+		var v T
+
+		err := decoder.Decode(&v)
+		if errors.Is(err, io.EOF) {
+			return
+		}
+
+		if err != nil {
+			var zero T
+
+			yield(zero, fmt.Errorf("%w: element %d: %w", ErrToDecodeBody, index, err))
+
+			return
+		}
+
+		if !yield(v, nil) {
+			return
+		}
+	}
+}
+
+// isNDJSONType reports whether contentType is a newline-delimited JSON media
+// type.
+func isNDJSONType(contentType string) bool {
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	mediaType = strings.TrimSpace(mediaType)
+
+	return strings.EqualFold(mediaType, mime.NDJSON.String()) ||
+		strings.EqualFold(mediaType, mime.JSONLines.String())
+}
+
+// responseReader returns the reader of the response body.
+func responseReader(resp contracts.Response) io.Reader {
+	if reader, ok := resp.Body().Raw().(io.Reader); ok {
+		return reader
+	}
+
+	return resp.Raw().Body
+}
+
+// peekNonSpace skips leading whitespace and returns the next byte without
+// consuming it.
+func peekNonSpace(reader *bufio.Reader) (byte, error) {
+	for {
+		b, err := reader.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+
+		switch b {
+		case ' ', '\t', '\r', '\n':
+			continue
+		}
+
+		return b, reader.UnreadByte()
+	}
+}
@@ -0,0 +1,154 @@
+package maigo
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+type streamEvent struct {
+	ID int `json:"id"`
+}
+
+// endlessEvents produces NDJSON events forever and records whether it was
+// closed.
+type endlessEvents struct {
+	next    int
+	pending []byte
+	closed  bool
+}
+
+func (e *endlessEvents) Read(p []byte) (int, error) {
+	if len(e.pending) == 0 {
+		e.pending = fmt.Appendf(nil, "{\"id\":%d}\n", e.next)
+		e.next++
+	}
+
+	n := copy(p, e.pending)
+	e.pending = e.pending[n:]
+
+	return n, nil
+}
+
+func (e *endlessEvents) Close() error {
+	e.closed = true
+	return nil
+}
+
+func streamResponse(body io.ReadCloser) *http.Response {
+	return &http.Response{StatusCode: http.StatusOK, Body: body}
+}
+
+func collectEvents(t *testing.T, body string) ([]int, error) {
+	t.Helper()
+
+	var ids []int
+
+	for event, err := range StreamJSON[streamEvent](NewResponse(streamResponse(io.NopCloser(strings.NewReader(body))))) {
+		if err != nil {
+			return ids, err
+		}
+
+		ids = append(ids, event.ID)
+	}
+
+	return ids, nil
+}
+
+func TestStreamJSON(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		body string
+		want []int
+	}{
+		{name: "ndjson", body: "{\"id\":1}\n{\"id\":2}\n\n{\"id\":3}\n", want: []int{1, 2, 3}},
+		{name: "array", body: " \n[{\"id\":1},\n{\"id\":2}]", want: []int{1, 2}},
+		{name: "empty array", body: "[]", want: nil},
+		{name: "empty body", body: "", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := collectEvents(t, tt.body)
+			if err != nil {
+				t.Fatalf("StreamJSON() error = %v", err)
+			}
+
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Fatalf("StreamJSON() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStreamJSON_NDJSONArrays(t *testing.T) {
+	t.Parallel()
+
+	for _, contentType := range []string{"application/x-ndjson", "application/jsonl; charset=utf-8"} {
+		raw := streamResponse(io.NopCloser(strings.NewReader("[1,2]\n[3]\n")))
+		raw.Header = http.Header{"Content-Type": {contentType}}
+
+		var got [][]int
+
+		for record, err := range StreamJSON[[]int](NewResponse(raw)) {
+			if err != nil {
+				t.Fatalf("StreamJSON(%s) error = %v", contentType, err)
+			}
+
+			got = append(got, record)
+		}
+
+		if fmt.Sprint(got) != "[[1 2] [3]]" {
+			t.Fatalf("StreamJSON(%s) = %v, want [[1 2] [3]]", contentType, got)
+		}
+	}
+}
+
+func TestStreamJSON_Errors(t *testing.T) {
+	t.Parallel()
+
+	for _, body := range []string{`[{"id":1},{"id":`, `{"id":1}` + "\n" + `{"id":"two"}`} {
+		got, err := collectEvents(t, body)
+		if !errors.Is(err, ErrToDecodeBody) {
+			t.Fatalf("StreamJSON(%q) error = %v, want %v", body, err, ErrToDecodeBody)
+		}
+
+		if len(got) != 1 || got[0] != 1 {
+			t.Fatalf("StreamJSON(%q) = %v, want [1] before the error", body, got)
+		}
+	}
+}
+
+func TestStreamJSON_BreakClosesBody(t *testing.T) {
+	t.Parallel()
+
+	body := &endlessEvents{}
+
+	var ids []int
+
+	for event, err := range StreamJSON[streamEvent](NewResponse(streamResponse(body))) {
+		if err != nil {
+			t.Fatalf("StreamJSON() error = %v", err)
+		}
+
+		ids = append(ids, event.ID)
+		if len(ids) == 3 {
+			break
+		}
+	}
+
+	if fmt.Sprint(ids) != "[0 1 2]" {
+		t.Fatalf("StreamJSON() = %v, want [0 1 2]", ids)
+	}
+
+	if !body.closed {
+		t.Fatalf("body not closed after leaving the loop")
+	}
+}
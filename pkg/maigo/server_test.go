@@ -0,0 +1,134 @@
+package maigo
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/jeanmolossi/maigo/pkg/maigo/header"
+)
+
+// testServer is an httptest.Server recording the requests it answers.
+type testServer struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	received []recordedRequest
+}
+
+// recordedRequest is a request received by a testServer.
+type recordedRequest struct {
+	header        http.Header
+	body          []byte
+	contentLength int64
+	chunked       bool
+}
+
+// newTestServer starts a server recording every request, body included,
+// before handler answers it. It is closed when the test ends.
+func newTestServer(t *testing.T, handler http.HandlerFunc) *testServer {
+	t.Helper()
+
+	server := &testServer{}
+
+	server.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		server.mu.Lock()
+		server.received = append(server.received, recordedRequest{
+			header:        r.Header.Clone(),
+			body:          body,
+			contentLength: r.ContentLength,
+			chunked:       slices.Contains(r.TransferEncoding, "chunked"),
+		})
+		server.mu.Unlock()
+
+		handler(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+// Requests returns the requests received so far.
+func (s *testServer) Requests() []recordedRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return slices.Clone(s.received)
+}
+
+// Hits returns the number of requests received so far.
+func (s *testServer) Hits() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.received)
+}
+
+// Header returns the value of key in each request received so far.
+func (s *testServer) Header(key string) []string {
+	var values []string
+	for _, request := range s.Requests() {
+		values = append(values, request.header.Get(key))
+	}
+
+	return values
+}
+
+// respond answers with status and body, setting the header key and value
+// pairs. An empty value removes the header, so an empty Content-Type is not
+// sniffed.
+func respond(status int, body string, header ...string) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		for i := 0; i+1 < len(header); i += 2 {
+			if header[i+1] == "" {
+				w.Header()[header[i]] = nil
+				continue
+			}
+
+			w.Header().Set(header[i], header[i+1])
+		}
+
+		if body != "" {
+			w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+		}
+
+		w.WriteHeader(status)
+		_, _ = io.WriteString(w, body)
+	}
+}
+
+// inTurn answers each request with the next handler, the last one answering
+// the remaining requests.
+func inTurn(handlers ...http.HandlerFunc) http.HandlerFunc {
+	var calls atomic.Int32
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		call := int(calls.Add(1)) - 1
+		handlers[min(call, len(handlers)-1)](w, r)
+	}
+}
+
+// echo answers with the request body and Content-Type, reporting the query,
+// X-Tag headers and cookies in X-Query, X-Tags and X-Cookies.
+func echo(w http.ResponseWriter, r *http.Request) {
+	var cookies []string
+	for _, cookie := range r.Cookies() {
+		cookies = append(cookies, cookie.Name+"="+cookie.Value)
+	}
+
+	w.Header().Set(header.ContentType.String(), r.Header.Get(header.ContentType.String()))
+	w.Header().Set("X-Query", r.URL.RawQuery)
+	w.Header().Set("X-Tags", strings.Join(r.Header.Values("X-Tag"), ","))
+	w.Header().Set("X-Cookies", strings.Join(cookies, ","))
+	_, _ = io.Copy(w, r.Body)
+}
@@ -0,0 +1,493 @@
+package maigo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jeanmolossi/maigo/pkg/maigo/contracts"
+	"github.com/jeanmolossi/maigo/pkg/maigo/header"
+	"github.com/jeanmolossi/maigo/pkg/maigo/mime"
+)
+
+var (
+	_ contracts.RefreshableAuthenticator = (*TokenSourceAuth)(nil)
+	_ contracts.TokenSource              = (*CachingTokenSource)(nil)
+	_ contracts.TokenSource              = (*ClientCredentialsSource)(nil)
+	_ contracts.TokenSource              = (*RefreshTokenSource)(nil)
+)
+
+const (
+	// defaultTokenExpiryDelta renews tokens slightly before they expire so a
+	// token does not expire while the request is in flight.
+	defaultTokenExpiryDelta = 10 * time.Second
+	// maxTokenResponseSize limits how much of a token endpoint response is read.
+	maxTokenResponseSize = 1 << 20 // 1MiB
+	// defaultTokenTimeout bounds a token fetch, so a hung endpoint does not
+	// hold every caller waiting on it.
+	defaultTokenTimeout = 30 * time.Second
+)
+
+// defaultTokenClient calls token endpoints when the config has no
+// HTTPClient.
+var defaultTokenClient = &http.Client{Timeout: defaultTokenTimeout}
+
+type (
+	// TokenSourceAuth sends tokens from a CachingTokenSource as the
+	// Authorization header and refreshes them after a 401 Unauthorized.
+	TokenSourceAuth struct {
+		source *CachingTokenSource
+	}
+
+	// CachingTokenSource caches the token of the wrapped source until it
+	// expires. Concurrent callers share a single in-flight request to the
+	// wrapped source, so the token endpoint is hit once no matter how many
+	// goroutines need a new token.
+	CachingTokenSource struct {
+		source      contracts.TokenSource
+		expiryDelta time.Duration
+		// timeout bounds each call to the wrapped source.
+		timeout time.Duration
+
+		mu    sync.Mutex
+		token *contracts.Token
+		call  *tokenCall
+	}
+
+	// tokenSourceCaches holds the caches of the token sources given to the
+	// requests of a client, so requests sending the same source share its
+	// token for as long as the client lives.
+	tokenSourceCaches struct {
+		mu     sync.Mutex
+		caches map[contracts.TokenSource]*CachingTokenSource
+	}
+
+	tokenCall struct {
+		done  chan struct{}
+		token *contracts.Token
+		err   error
+	}
+
+	// ClientCredentialsConfig describes the OAuth2 client credentials grant
+	// (RFC 6749 §4.4).
+	ClientCredentialsConfig struct {
+		// TokenURL is the token endpoint of the authorization server.
+		TokenURL string
+		// ClientID is the application's ID.
+		ClientID string
+		// ClientSecret is the application's secret.
+		ClientSecret string
+		// Scopes optionally requests specific permissions.
+		Scopes []string
+		// EndpointParams holds additional parameters sent to the token endpoint.
+		EndpointParams url.Values
+		// AuthInBody sends the client credentials in the request body instead
+		// of the Basic Authorization header.
+		AuthInBody bool
+		// HTTPClient is used to call the token endpoint. Defaults to a
+		// client with a 30 seconds timeout.
+		HTTPClient *http.Client
+	}
+
+	// RefreshTokenConfig describes the OAuth2 refresh token grant
+	// (RFC 6749 §6).
+	RefreshTokenConfig struct {
+		// TokenURL is the token endpoint of the authorization server.
+		TokenURL string
+		// ClientID is the application's ID.
+		ClientID string
+		// ClientSecret is the application's secret.
+		ClientSecret string
+		// RefreshToken is the initial refresh token. It is replaced when the
+		// server rotates refresh tokens.
+		RefreshToken string
+		// Scopes optionally narrows the permissions of the new access token.
+		Scopes []string
+		// AuthInBody sends the client credentials in the request body instead
+		// of the Basic Authorization header.
+		AuthInBody bool
+		// HTTPClient is used to call the token endpoint. Defaults to a
+		// client with a 30 seconds timeout.
+		HTTPClient *http.Client
+	}
+
+	// ClientCredentialsSource obtains tokens with the client credentials grant.
+	ClientCredentialsSource struct {
+		config ClientCredentialsConfig
+	}
+
+	// RefreshTokenSource obtains tokens with the refresh token grant, keeping
+	// track of rotated refresh tokens.
+	RefreshTokenSource struct {
+		config RefreshTokenConfig
+
+		mu           sync.Mutex
+		refreshToken string
+	}
+
+	// tokenResponse is the successful token response defined by RFC 6749 §5.1.
+	tokenResponse struct {
+		AccessToken  string          `json:"access_token"`
+		TokenType    string          `json:"token_type"`
+		RefreshToken string          `json:"refresh_token"`
+		ExpiresIn    json.RawMessage `json:"expires_in"`
+	}
+
+	// tokenErrorResponse is the error response defined by RFC 6749 §5.2.
+	tokenErrorResponse struct {
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+)
+
+// NewTokenSourceAuth creates an authenticator sending tokens from source.
+// Sources that are not a *CachingTokenSource are wrapped by one.
+func NewTokenSourceAuth(source contracts.TokenSource) *TokenSourceAuth {
+	if caching, ok := source.(*CachingTokenSource); ok {
+		return &TokenSourceAuth{source: caching}
+	}
+
+	return &TokenSourceAuth{source: NewCachingTokenSource(source)}
+}
+
+func newTokenSourceCaches() *tokenSourceCaches {
+	return &tokenSourceCaches{caches: make(map[contracts.TokenSource]*CachingTokenSource)}
+}
+
+// authFor returns an authenticator sending tokens from the cache of source,
+// creating it on first use. Only pointer sources are looked up, other ones,
+// which may hold values that cannot be map keys, get a cache of their own.
+func (t *tokenSourceCaches) authFor(source contracts.TokenSource) *TokenSourceAuth {
+	if _, ok := source.(*CachingTokenSource); ok || t == nil || reflect.TypeOf(source).Kind() != reflect.Pointer {
+		return NewTokenSourceAuth(source)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	caching, ok := t.caches[source]
+	if !ok {
+		caching = NewCachingTokenSource(source)
+		t.caches[source] = caching
+	}
+
+	return &TokenSourceAuth{source: caching}
+}
+
+// Authenticate implements contracts.Authenticator.
+func (t *TokenSourceAuth) Authenticate(r *http.Request) error {
+	token, err := t.source.Token(r.Context())
+	if err != nil {
+		return err
+	}
+
+	r.Header.Set(header.Authorization.String(), authorizationValue(token))
+
+	return nil
+}
+
+// Refresh implements contracts.RefreshableAuthenticator. Only the token sent
+// with r is discarded, so concurrent requests rejected with the same token
+// trigger a single renewal.
+func (t *TokenSourceAuth) Refresh(r *http.Request) (bool, error) {
+	sent := r.Header.Get(header.Authorization.String())
+
+	token, err := t.source.Refresh(r.Context(), func(current *contracts.Token) bool {
+		return authorizationValue(current) == sent
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return authorizationValue(token) != sent, nil
+}
+
+// NewCachingTokenSource wraps source with a cache shared by all callers.
+func NewCachingTokenSource(source contracts.TokenSource) *CachingTokenSource {
+	return &CachingTokenSource{
+		source:      source,
+		expiryDelta: defaultTokenExpiryDelta,
+		timeout:     defaultTokenTimeout,
+	}
+}
+
+// Token implements contracts.TokenSource. It returns the cached token while it
+// is valid, otherwise it waits for a new one.
+func (c *CachingTokenSource) Token(ctx context.Context) (*contracts.Token, error) {
+	c.mu.Lock()
+
+	if c.valid(c.token) {
+		token := c.token
+		c.mu.Unlock()
+
+		return token, nil
+	}
+
+	return c.fetchLocked(ctx)
+}
+
+// Refresh discards the cached token when stale reports it as the rejected one
+// and waits for a new token. When another caller already replaced it, the
+// current token is returned without contacting the wrapped source.
+func (c *CachingTokenSource) Refresh(ctx context.Context, stale func(*contracts.Token) bool) (*contracts.Token, error) {
+	c.mu.Lock()
+
+	if c.token != nil && stale(c.token) {
+		c.token = nil
+	}
+
+	if c.valid(c.token) {
+		token := c.token
+		c.mu.Unlock()
+
+		return token, nil
+	}
+
+	return c.fetchLocked(ctx)
+}
+
+// fetchLocked joins the in-flight call or starts a new one. It must be called
+// with c.mu held and releases it. The call runs detached from ctx so a caller
+// giving up does not fail the other waiters, bounded by its own timeout so a
+// hung endpoint does not hold the ones coming later.
+func (c *CachingTokenSource) fetchLocked(ctx context.Context) (*contracts.Token, error) {
+	call := c.call
+	if call == nil {
+		call = &tokenCall{done: make(chan struct{})}
+		c.call = call
+
+		fetchCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), c.timeout)
+
+		go func() {
+			defer cancel()
+			c.fetch(fetchCtx, call)
+		}()
+	}
+
+	c.mu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-call.done:
+		return call.token, call.err
+	}
+}
+
+func (c *CachingTokenSource) fetch(ctx context.Context, call *tokenCall) {
+	token, err := c.source.Token(ctx)
+	if err == nil && (token == nil || token.AccessToken == "") {
+		err = ErrEmptyToken
+	}
+
+	if err != nil {
+		token = nil
+	}
+
+	c.mu.Lock()
+
+	if err == nil {
+		c.token = token
+	}
+
+	c.call = nil
+	c.mu.Unlock()
+
+	call.token, call.err = token, err
+	close(call.done)
+}
+
+func (c *CachingTokenSource) valid(token *contracts.Token) bool {
+	if token == nil || token.AccessToken == "" {
+		return false
+	}
+
+	return token.Expiry.IsZero() || time.Now().Add(c.expiryDelta).Before(token.Expiry)
+}
+
+// NewClientCredentialsSource creates a token source for the client
+// credentials grant. Wrap it with NewCachingTokenSource, or use it through
+// BuilderAuth.TokenSource, to avoid requesting a token for every request.
+func NewClientCredentialsSource(config ClientCredentialsConfig) *ClientCredentialsSource {
+	return &ClientCredentialsSource{config: config}
+}
+
+// Token implements contracts.TokenSource.
+func (c *ClientCredentialsSource) Token(ctx context.Context) (*contracts.Token, error) {
+	form := url.Values{"grant_type": {"client_credentials"}}
+
+	if len(c.config.Scopes) > 0 {
+		form.Set("scope", strings.Join(c.config.Scopes, " "))
+	}
+
+	for key, values := range c.config.EndpointParams {
+		form[key] = append(form[key], values...)
+	}
+
+	return requestToken(ctx, tokenRequest{
+		httpClient:   c.config.HTTPClient,
+		tokenURL:     c.config.TokenURL,
+		clientID:     c.config.ClientID,
+		clientSecret: c.config.ClientSecret,
+		authInBody:   c.config.AuthInBody,
+		form:         form,
+	})
+}
+
+// NewRefreshTokenSource creates a token source for the refresh token grant.
+func NewRefreshTokenSource(config RefreshTokenConfig) *RefreshTokenSource {
+	return &RefreshTokenSource{
+		config:       config,
+		refreshToken: config.RefreshToken,
+	}
+}
+
+// Token implements contracts.TokenSource.
+func (r *RefreshTokenSource) Token(ctx context.Context) (*contracts.Token, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.refreshToken == "" {
+		return nil, fmt.Errorf("%w: empty refresh token", ErrTokenRequest)
+	}
+
+	form := url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {r.refreshToken},
+	}
+
+	if len(r.config.Scopes) > 0 {
+		form.Set("scope", strings.Join(r.config.Scopes, " "))
+	}
+
+	token, err := requestToken(ctx, tokenRequest{
+		httpClient:   r.config.HTTPClient,
+		tokenURL:     r.config.TokenURL,
+		clientID:     r.config.ClientID,
+		clientSecret: r.config.ClientSecret,
+		authInBody:   r.config.AuthInBody,
+		form:         form,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// servers may rotate refresh tokens; keep the previous one otherwise.
+	if token.RefreshToken != "" {
+		r.refreshToken = token.RefreshToken
+	} else {
+		token.RefreshToken = r.refreshToken
+	}
+
+	return token, nil
+}
+
+type tokenRequest struct {
+	httpClient   *http.Client
+	tokenURL     string
+	clientID     string
+	clientSecret string
+	authInBody   bool
+	form         url.Values
+}
+
+func requestToken(ctx context.Context, tr tokenRequest) (*contracts.Token, error) {
+	if tr.authInBody {
+		tr.form.Set("client_id", tr.clientID)
+
+		if tr.clientSecret != "" {
+			tr.form.Set("client_secret", tr.clientSecret)
+		}
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, tr.tokenURL, strings.NewReader(tr.form.Encode()))
+	if err != nil {
+		return nil, errors.Join(ErrTokenRequest, err)
+	}
+
+	request.Header.Set(header.ContentType.String(), mime.FormURLEncoded.String())
+	request.Header.Set(header.Accept.String(), mime.JSON.String())
+
+	if !tr.authInBody {
+		request.SetBasicAuth(url.QueryEscape(tr.clientID), url.QueryEscape(tr.clientSecret))
+	}
+
+	httpClient := tr.httpClient
+	if httpClient == nil {
+		httpClient = defaultTokenClient
+	}
+
+	response, err := httpClient.Do(request)
+	if err != nil {
+		return nil, errors.Join(ErrTokenRequest, err)
+	}
+	defer response.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(response.Body, maxTokenResponseSize))
+	if err != nil {
+		return nil, errors.Join(ErrTokenRequest, err)
+	}
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		var tokenErr tokenErrorResponse
+		if json.Unmarshal(data, &tokenErr) == nil && tokenErr.Error != "" {
+			return nil, fmt.Errorf("%w: [%d] %s: %s", ErrTokenRequest, response.StatusCode, tokenErr.Error, tokenErr.ErrorDescription)
+		}
+
+		return nil, fmt.Errorf("%w: [%d] %s", ErrTokenRequest, response.StatusCode, http.StatusText(response.StatusCode))
+	}
+
+	var payload tokenResponse
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, errors.Join(ErrTokenRequest, err)
+	}
+
+	if payload.AccessToken == "" {
+		return nil, ErrEmptyToken
+	}
+
+	token := &contracts.Token{
+		AccessToken:  payload.AccessToken,
+		TokenType:    payload.TokenType,
+		RefreshToken: payload.RefreshToken,
+	}
+
+	if seconds, ok := parseExpiresIn(payload.ExpiresIn); ok {
+		token.Expiry = time.Now().Add(time.Duration(seconds) * time.Second)
+	}
+
+	return token, nil
+}
+
+// parseExpiresIn accepts expires_in as a number or, as sent by some servers,
+// as a string.
+func parseExpiresIn(raw json.RawMessage) (int64, bool) {
+	seconds, err := strconv.ParseInt(strings.Trim(string(raw), `"`), 10, 64)
+	if err != nil || seconds <= 0 {
+		return 0, false
+	}
+
+	return seconds, true
+}
+
+func authorizationValue(token *contracts.Token) string {
+	if token == nil {
+		return ""
+	}
+
+	tokenType := token.TokenType
+	if tokenType == "" || strings.EqualFold(tokenType, "bearer") {
+		tokenType = "Bearer"
+	}
+
+	return tokenType + " " + token.AccessToken
+}
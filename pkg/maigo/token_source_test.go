@@ -0,0 +1,359 @@
+package maigo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jeanmolossi/maigo/pkg/maigo/contracts"
+)
+
+// issueTokens issues "token-1", "token-2", ... for the client credentials
+// grant and counts how many tokens were issued.
+func issueTokens(hits *atomic.Int32) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil || r.PostForm.Get("grant_type") != "client_credentials" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = io.WriteString(w, `{"error":"unsupported_grant_type"}`)
+
+			return
+		}
+
+		if user, pass, ok := r.BasicAuth(); !ok || user != "mai" || pass != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = io.WriteString(w, `{"error":"invalid_client","error_description":"bad credentials"}`)
+
+			return
+		}
+
+		// simulate a slow endpoint so concurrent callers pile up
+		time.Sleep(20 * time.Millisecond)
+
+		n := hits.Add(1)
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"access_token":"token-%d","token_type":"bearer","expires_in":"3600"}`, n)
+	}
+}
+
+// protected only accepts the given token and echoes the request body.
+func protected(accepted string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+accepted {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		_, _ = io.Copy(w, r.Body)
+	}
+}
+
+func TestTokenSource_CachesToken(t *testing.T) {
+	t.Parallel()
+
+	var hits atomic.Int32
+
+	tokenServer := newTestServer(t, issueTokens(&hits))
+	api := newTestServer(t, protected("token-1"))
+
+	client := NewClient(api.URL).
+		Auth().TokenSource(NewClientCredentialsSource(ClientCredentialsConfig{
+		TokenURL:     tokenServer.URL,
+		ClientID:     "mai",
+		ClientSecret: "secret",
+	})).
+		Build()
+
+	for range 3 {
+		resp, err := client.GET("/").Send()
+		if err != nil {
+			t.Fatalf("Send() error = %v", err)
+		}
+
+		if !resp.Status().IsOK() {
+			t.Fatalf("status = %d, want 200", resp.Status().Code())
+		}
+	}
+
+	if got := hits.Load(); got != 1 {
+		t.Fatalf("token endpoint hits = %d, want 1", got)
+	}
+}
+
+func TestTokenSource_RefreshesOnceOnUnauthorized(t *testing.T) {
+	t.Parallel()
+
+	var hits atomic.Int32
+
+	tokenServer := newTestServer(t, issueTokens(&hits))
+	// the first token is already revoked upstream
+	api := newTestServer(t, protected("token-2"))
+
+	source := NewCachingTokenSource(NewClientCredentialsSource(ClientCredentialsConfig{
+		TokenURL:     tokenServer.URL,
+		ClientID:     "mai",
+		ClientSecret: "secret",
+	}))
+
+	// warm the cache with the revoked token
+	if _, err := source.Token(context.Background()); err != nil {
+		t.Fatalf("Token() error = %v", err)
+	}
+
+	client := NewClient(api.URL).Auth().TokenSource(source).Build()
+
+	const concurrent = 10
+
+	var wg sync.WaitGroup
+
+	errs := make(chan error, concurrent)
+
+	for i := range concurrent {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			payload := fmt.Sprintf("payload-%d", i)
+
+			resp, err := client.POST("/").Body().AsString(payload).Send()
+			if err != nil {
+				errs <- err
+				return
+			}
+
+			body, err := resp.Body().AsString()
+			if err != nil {
+				errs <- err
+				return
+			}
+
+			if !resp.Status().IsOK() || body != payload {
+				errs <- fmt.Errorf("got [%d] %q, want [200] %q", resp.Status().Code(), body, payload)
+			}
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Error(err)
+	}
+
+	if got := hits.Load(); got != 2 {
+		t.Fatalf("token endpoint hits = %d, want 2", got)
+	}
+}
+
+func TestTokenSource_EndpointError(t *testing.T) {
+	t.Parallel()
+
+	var hits atomic.Int32
+
+	tokenServer := newTestServer(t, issueTokens(&hits))
+
+	_, err := DefaultClient("https://example.com").
+		GET("/").
+		Auth().TokenSource(NewClientCredentialsSource(ClientCredentialsConfig{
+		TokenURL:     tokenServer.URL,
+		ClientID:     "mai",
+		ClientSecret: "wrong",
+	})).
+		Unwrap()
+
+	if !errors.Is(err, ErrAuthenticate) || !errors.Is(err, ErrTokenRequest) {
+		t.Fatalf("Unwrap() error = %v, want %v and %v", err, ErrAuthenticate, ErrTokenRequest)
+	}
+}
+
+type staticTokenSource struct {
+	calls atomic.Int32
+}
+
+func (s *staticTokenSource) Token(context.Context) (*contracts.Token, error) {
+	s.calls.Add(1)
+
+	return &contracts.Token{AccessToken: "static"}, nil
+}
+
+func TestTokenSource_NoReplayWhenTokenUnchanged(t *testing.T) {
+	t.Parallel()
+
+	var apiHits atomic.Int32
+
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		apiHits.Add(1)
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	t.Cleanup(api.Close)
+
+	source := &staticTokenSource{}
+
+	resp, err := NewClient(api.URL).Auth().TokenSource(source).Build().GET("/").Send()
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	if !resp.Status().IsUnauthorized() {
+		t.Fatalf("status = %d, want 401", resp.Status().Code())
+	}
+
+	if got := apiHits.Load(); got != 1 {
+		t.Fatalf("api hits = %d, want 1", got)
+	}
+
+	if got := source.calls.Load(); got != 2 {
+		t.Fatalf("token source calls = %d, want 2", got)
+	}
+}
+
+func TestRefreshTokenSource_RotatesRefreshToken(t *testing.T) {
+	t.Parallel()
+
+	var seen []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		seen = append(seen, r.PostForm.Get("refresh_token"))
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"access_token":"access-%d","refresh_token":"refresh-%d","expires_in":60}`, len(seen), len(seen))
+	}))
+	t.Cleanup(server.Close)
+
+	source := NewRefreshTokenSource(RefreshTokenConfig{
+		TokenURL:     server.URL,
+		ClientID:     "mai",
+		RefreshToken: "refresh-0",
+	})
+
+	for range 2 {
+		token, err := source.Token(context.Background())
+		if err != nil {
+			t.Fatalf("Token() error = %v", err)
+		}
+
+		if token.Expiry.IsZero() {
+			t.Fatalf("Token() expiry not set")
+		}
+	}
+
+	if len(seen) != 2 || seen[0] != "refresh-0" || seen[1] != "refresh-1" {
+		t.Fatalf("refresh tokens sent = %v, want [refresh-0 refresh-1]", seen)
+	}
+}
+
+func TestTokenSource_CachedPerSourceAcrossRequests(t *testing.T) {
+	t.Parallel()
+
+	source := &staticTokenSource{}
+	api := newTestServer(t, protected("static"))
+	client := DefaultClient(api.URL)
+
+	for range 5 {
+		resp, err := client.GET("/").Auth().TokenSource(source).Send()
+		if err != nil {
+			t.Fatalf("Send() error = %v", err)
+		}
+
+		if !resp.Status().IsOK() {
+			t.Fatalf("status = %d, want 200", resp.Status().Code())
+		}
+	}
+
+	if got := source.calls.Load(); got != 1 {
+		t.Fatalf("token fetches = %d, want 1", got)
+	}
+}
+
+func TestTokenSource_CachedPerClient(t *testing.T) {
+	t.Parallel()
+
+	source := &staticTokenSource{}
+	api := newTestServer(t, protected("static"))
+
+	for range 2 {
+		if _, err := DefaultClient(api.URL).GET("/").Auth().TokenSource(source).Send(); err != nil {
+			t.Fatalf("Send() error = %v", err)
+		}
+	}
+
+	if got := source.calls.Load(); got != 2 {
+		t.Fatalf("token fetches = %d, want one per client", got)
+	}
+}
+
+// funcTokenSource is a comparable type holding a value that is not.
+type funcTokenSource struct {
+	fetch any
+}
+
+func (s funcTokenSource) Token(ctx context.Context) (*contracts.Token, error) {
+	return s.fetch.(func(context.Context) (*contracts.Token, error))(ctx)
+}
+
+func TestTokenSource_UncomparableValueSource(t *testing.T) {
+	t.Parallel()
+
+	source := funcTokenSource{fetch: func(context.Context) (*contracts.Token, error) {
+		return &contracts.Token{AccessToken: "static"}, nil
+	}}
+
+	api := newTestServer(t, protected("static"))
+
+	resp, err := DefaultClient(api.URL).GET("/").Auth().TokenSource(source).Send()
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	if !resp.Status().IsOK() {
+		t.Fatalf("status = %d, want 200", resp.Status().Code())
+	}
+}
+
+// hangingTokenSource blocks its first call until the context is done.
+type hangingTokenSource struct {
+	calls atomic.Int32
+}
+
+func (s *hangingTokenSource) Token(ctx context.Context) (*contracts.Token, error) {
+	if s.calls.Add(1) == 1 {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+
+	return &contracts.Token{AccessToken: "late"}, nil
+}
+
+func TestCachingTokenSource_FetchTimeout(t *testing.T) {
+	t.Parallel()
+
+	caching := NewCachingTokenSource(&hangingTokenSource{})
+	caching.timeout = 20 * time.Millisecond
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	// the hung fetch fails on its own deadline, not the caller's
+	start := time.Now()
+	if _, err := caching.Token(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Token() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Fatalf("Token() waited %v for a hung fetch", elapsed)
+	}
+
+	token, err := caching.Token(ctx)
+	if err != nil || token.AccessToken != "late" {
+		t.Fatalf("Token() = %v, %v, want the late token", token, err)
+	}
+}
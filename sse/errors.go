@@ -0,0 +1,10 @@
+package sse
+
+import "errors"
+
+var (
+	ErrNilRequestBuilder     = errors.New("nil request builder is not allowed")
+	ErrUnexpectedStatus      = errors.New("unexpected event stream status")
+	ErrUnexpectedContentType = errors.New("response is not an event stream")
+	ErrTooManyReconnects     = errors.New("too many reconnect attempts")
+)
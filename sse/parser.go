@@ -0,0 +1,136 @@
+package sse
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const maxLineSize = 1 << 20 // 1MiB
+
+// parser reads events from a text/event-stream body as defined by the HTML
+// Living Standard. The last event id and the reconnection time outlive a
+// single connection, so they are kept by the stream.
+type parser struct {
+	scanner   *bufio.Scanner
+	lastID    *string
+	retry     *time.Duration
+	onComment func(comment string)
+	started   bool
+}
+
+func newParser(body io.Reader, lastID *string, retry *time.Duration, onComment func(string)) *parser {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 4096), maxLineSize)
+	scanner.Split(scanLines)
+
+	return &parser{
+		scanner:   scanner,
+		lastID:    lastID,
+		retry:     retry,
+		onComment: onComment,
+	}
+}
+
+// next returns the next event. It returns io.EOF once the body ends; an
+// event not terminated by a blank line is discarded.
+func (p *parser) next() (Event, error) {
+	var (
+		data      strings.Builder
+		eventType string
+		hasData   bool
+	)
+
+	for p.scanner.Scan() {
+		line := p.scanner.Text()
+
+		if !p.started {
+			line = strings.TrimPrefix(line, "\ufeff")
+			p.started = true
+		}
+
+		if line == "" {
+			if !hasData {
+				eventType = ""
+				continue
+			}
+
+			if eventType == "" {
+				eventType = "message"
+			}
+
+			return Event{
+				ID:   *p.lastID,
+				Type: eventType,
+				Data: strings.TrimSuffix(data.String(), "\n"),
+			}, nil
+		}
+
+		if comment, ok := strings.CutPrefix(line, ":"); ok {
+			if p.onComment != nil {
+				p.onComment(strings.TrimPrefix(comment, " "))
+			}
+
+			continue
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+
+		switch field {
+		case "event":
+			eventType = value
+		case "data":
+			data.WriteString(value)
+			data.WriteByte('\n')
+
+			hasData = true
+		case "id":
+			if !strings.ContainsRune(value, 0) {
+				*p.lastID = value
+			}
+		case "retry":
+			if ms, err := strconv.ParseUint(value, 10, 32); err == nil {
+				*p.retry = time.Duration(ms) * time.Millisecond
+			}
+		}
+	}
+
+	if err := p.scanner.Err(); err != nil {
+		return Event{}, err
+	}
+
+	return Event{}, io.EOF
+}
+
+// scanLines splits lines ended by CRLF, LF or CR.
+func scanLines(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	if atEOF && len(data) == 0 {
+		return 0, nil, nil
+	}
+
+	if i := bytes.IndexAny(data, "\r\n"); i >= 0 {
+		if data[i] == '\n' {
+			return i + 1, data[:i], nil
+		}
+
+		// a CR may be followed by a LF not read yet
+		switch {
+		case i+1 < len(data) && data[i+1] == '\n':
+			return i + 2, data[:i], nil
+		case i+1 < len(data) || atEOF:
+			return i + 1, data[:i], nil
+		default:
+			return 0, nil, nil
+		}
+	}
+
+	if atEOF {
+		return len(data), data, nil
+	}
+
+	return 0, nil, nil
+}
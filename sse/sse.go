@@ -0,0 +1,326 @@
+// Package sse consumes Server-Sent Events (text/event-stream) sent in
+// response to a MaiGo request, reconnecting with the Last-Event-ID header
+// whenever the stream is interrupted.
+package sse
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/jeanmolossi/maigo/pkg/maigo"
+	"github.com/jeanmolossi/maigo/pkg/maigo/contracts"
+	"github.com/jeanmolossi/maigo/pkg/maigo/header"
+	"github.com/jeanmolossi/maigo/pkg/maigo/mime"
+)
+
+const (
+	defaultReconnectDelay = 3 * time.Second
+	maxBackoff            = 30 * time.Second
+)
+
+// Event is a message received from the stream.
+type Event struct {
+	// ID is the last event id set by the stream, sent back as
+	// Last-Event-ID when reconnecting.
+	ID string
+	// Type is the event name, "message" unless the server sets one.
+	Type string
+	// Data holds the data lines of the event joined by newlines.
+	Data string
+}
+
+// Config contains settings for consuming a stream. The zero value is ready
+// to use.
+type Config struct {
+	// LastEventID resumes a stream from the given event id.
+	LastEventID string
+	// ReconnectDelay is the delay before reconnecting until the server sends
+	// a retry field replacing it. Defaults to 3 seconds.
+	ReconnectDelay time.Duration
+	// Backoff computes the delay before the next connection from the number
+	// of consecutive failed connections, starting at zero, and the current
+	// reconnect delay. Defaults to doubling the delay on each failure up to
+	// 30 seconds.
+	Backoff func(attempt int, delay time.Duration) time.Duration
+	// MaxReconnects bounds the consecutive failed connections before giving
+	// up. Zero means unlimited.
+	MaxReconnects int
+	// OnComment is invoked with the comment lines of the stream, often sent
+	// as keep-alives.
+	OnComment func(comment string)
+	// OnReconnect is invoked before waiting to reconnect, with the error
+	// that ended the previous connection, if any.
+	OnReconnect func(attempt int, err error, delay time.Duration)
+}
+
+// Events sends req and yields the events of the stream it returns,
+// reconnecting whenever the connection ends or fails. It stops when the
+// loop is left, when the server answers 204 No Content, when the request
+// context is done or on a non retryable error, which is yielded last.
+//
+// Every connection sends a clone of req with the Accept, Cache-Control and
+// Last-Event-ID headers set, so req itself is left untouched.
+//
+// Example:
+//
+//	for event, err := range sse.Events(client.GET("/updates"), sse.Config{}) {
+//	    if err != nil {
+//	        return err
+//	    }
+//	    ...
+//	}
+func Events(req contracts.RequestBuilder, cfg Config) iter.Seq2[Event, error] {
+	return func(yield func(Event, error) bool) {
+		s, err := newStream(req, cfg)
+		if err != nil {
+			yield(Event{}, err)
+			return
+		}
+
+		s.run(yield)
+	}
+}
+
+// Subscribe is like Events, delivering the events through a channel. The
+// error channel receives the error that ended the stream, if any, and both
+// channels are closed once it ends. Delivery stops when the request context
+// is done, so the context must be cancelled when the events are no longer
+// read.
+func Subscribe(req contracts.RequestBuilder, cfg Config) (<-chan Event, <-chan error) {
+	events := make(chan Event)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(errs)
+		defer close(events)
+
+		s, err := newStream(req, cfg)
+		if err != nil {
+			errs <- err
+			return
+		}
+
+		s.run(func(event Event, err error) bool {
+			if err != nil {
+				errs <- err
+				return false
+			}
+
+			select {
+			case events <- event:
+				return true
+			case <-s.ctx.Done():
+				errs <- s.ctx.Err()
+				return false
+			}
+		})
+	}()
+
+	return events, errs
+}
+
+type stream struct {
+	req contracts.RequestBuilder
+	cfg Config
+	ctx context.Context
+
+	lastID string
+	retry  time.Duration
+}
+
+func newStream(req contracts.RequestBuilder, cfg Config) (*stream, error) {
+	if req == nil {
+		return nil, ErrNilRequestBuilder
+	}
+
+	// validates the request and exposes its context before connecting
+	raw, err := req.Unwrap()
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.ReconnectDelay <= 0 {
+		cfg.ReconnectDelay = defaultReconnectDelay
+	}
+
+	if cfg.Backoff == nil {
+		cfg.Backoff = defaultBackoff
+	}
+
+	return &stream{
+		req:    req,
+		cfg:    cfg,
+		ctx:    raw.Context(),
+		lastID: cfg.LastEventID,
+		retry:  cfg.ReconnectDelay,
+	}, nil
+}
+
+func (s *stream) run(yield func(Event, error) bool) {
+	failures := 0
+
+	for {
+		received, reconnect, err := s.connect(yield)
+		if !reconnect {
+			if err != nil {
+				yield(Event{}, err)
+			}
+
+			return
+		}
+
+		// a connection that delivered events resets the backoff
+		if received {
+			failures = 0
+		}
+
+		if s.cfg.MaxReconnects > 0 && failures >= s.cfg.MaxReconnects {
+			yield(Event{}, errors.Join(ErrTooManyReconnects, err))
+			return
+		}
+
+		delay := s.cfg.Backoff(failures, s.retry)
+		if s.cfg.OnReconnect != nil {
+			s.cfg.OnReconnect(failures, err, delay)
+		}
+
+		failures++
+
+		if err := sleepCtx(s.ctx, delay); err != nil {
+			yield(Event{}, err)
+			return
+		}
+	}
+}
+
+// connect opens the stream and yields its events until it ends. It reports
+// whether any event was received, whether the stream should be reopened and
+// the error that ended it.
+func (s *stream) connect(yield func(Event, error) bool) (received, reconnect bool, err error) {
+	req := s.req.Clone()
+	req.Header().Set(header.Accept, mime.EventStream.String())
+	req.Header().Set(header.CacheControl, "no-cache")
+
+	if s.lastID != "" {
+		req.Header().Set(header.LastEventID, s.lastID)
+	} else {
+		// an empty id field resets the last event id
+		req.Header().Remove(header.LastEventID)
+	}
+
+	// the status is checked below, even with ExpectSuccess
+	resp, err := req.SendAnyStatus()
+	if err != nil {
+		if ctxErr := s.ctx.Err(); ctxErr != nil {
+			return false, false, ctxErr
+		}
+
+		// a status reported by an interceptor
+		var httpErr *maigo.HTTPError[[]byte]
+		if errors.As(err, &httpErr) {
+			return false, isTransient(httpErr.StatusCode), err
+		}
+
+		return false, true, err
+	}
+
+	defer resp.Body().Close()
+
+	if err := checkResponse(resp); err != nil {
+		return false, isTransient(resp.Status().Code()), err
+	}
+
+	if resp.Status().IsNoContent() {
+		return false, false, nil
+	}
+
+	p := newParser(resp.Raw().Body, &s.lastID, &s.retry, s.cfg.OnComment)
+
+	for {
+		event, err := p.next()
+		if err != nil {
+			if ctxErr := s.ctx.Err(); ctxErr != nil {
+				return received, false, ctxErr
+			}
+
+			if errors.Is(err, io.EOF) {
+				return received, true, nil
+			}
+
+			return received, true, err
+		}
+
+		received = true
+
+		if !yield(event, nil) {
+			return received, false, nil
+		}
+	}
+}
+
+// checkResponse fails responses that are not an event stream, except 204
+// No Content, which ends the stream.
+func checkResponse(resp contracts.Response) error {
+	status := resp.Status()
+
+	if status.IsNoContent() {
+		return nil
+	}
+
+	if !status.Is2xxSuccessful() {
+		return fmt.Errorf("%w: %d %s", ErrUnexpectedStatus, status.Code(), status.Text())
+	}
+
+	contentType, _, _ := strings.Cut(resp.Header().Get(header.ContentType.String()), ";")
+	if !strings.EqualFold(strings.TrimSpace(contentType), mime.EventStream.String()) {
+		return fmt.Errorf("%w: %q", ErrUnexpectedContentType, contentType)
+	}
+
+	return nil
+}
+
+// isTransient reports whether a failed connection with status code may
+// succeed when reopened.
+func isTransient(code int) bool {
+	switch code {
+	case http.StatusRequestTimeout, http.StatusTooManyRequests,
+		http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+func defaultBackoff(attempt int, delay time.Duration) time.Duration {
+	for range attempt {
+		if delay >= maxBackoff {
+			break
+		}
+
+		delay *= 2
+	}
+
+	return min(delay, maxBackoff)
+}
+
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
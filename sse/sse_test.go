@@ -0,0 +1,326 @@
+package sse
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jeanmolossi/maigo/pkg/maigo"
+)
+
+func TestParser(t *testing.T) {
+	t.Parallel()
+
+	body := "\ufeff: keep-alive\r\n" +
+		"retry: 1500\r\n" +
+		"id: 7\r" +
+		"event: update\n" +
+		"data: first\n" +
+		"data:second\n\n" +
+		"data\n\n" +
+		"event: ignored\n\n" +
+		"id\n" +
+		"data: last\n\n" +
+		"data: truncated"
+
+	var (
+		lastID   string
+		retry    time.Duration
+		comments []string
+	)
+
+	p := newParser(strings.NewReader(body), &lastID, &retry, func(c string) { comments = append(comments, c) })
+
+	want := []Event{
+		{ID: "7", Type: "update", Data: "first\nsecond"},
+		{ID: "7", Type: "message", Data: ""},
+		{ID: "", Type: "message", Data: "last"},
+	}
+
+	for _, w := range want {
+		got, err := p.next()
+		if err != nil {
+			t.Fatalf("next() error = %v", err)
+		}
+
+		if got != w {
+			t.Fatalf("next() = %+v, want %+v", got, w)
+		}
+	}
+
+	if _, err := p.next(); !errors.Is(err, io.EOF) {
+		t.Fatalf("next() error = %v, want %v", err, io.EOF)
+	}
+
+	if retry != 1500*time.Millisecond {
+		t.Fatalf("retry = %s, want 1.5s", retry)
+	}
+
+	if len(comments) != 1 || comments[0] != "keep-alive" {
+		t.Fatalf("comments = %q, want [keep-alive]", comments)
+	}
+}
+
+func writeStream(w http.ResponseWriter, body string) {
+	w.Header().Set("Content-Type", "text/event-stream")
+	_, _ = io.WriteString(w, body)
+}
+
+func TestEvents_ReconnectsWithLastEventID(t *testing.T) {
+	t.Parallel()
+
+	var (
+		hits    atomic.Int32
+		resumed atomic.Value
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept") != "text/event-stream" {
+			w.WriteHeader(http.StatusNotAcceptable)
+			return
+		}
+
+		if hits.Add(1) == 1 {
+			// a short retry, otherwise the test would wait an hour
+			writeStream(w, "retry: 5\nid: 1\ndata: one\n\n")
+			return
+		}
+
+		resumed.Store(r.Header.Get("Last-Event-ID"))
+		writeStream(w, "id: 2\ndata: two\n\n")
+	}))
+	t.Cleanup(server.Close)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	req := maigo.DefaultClient(server.URL).GET("/").Context().Set(ctx)
+
+	var got []string
+
+	for event, err := range Events(req, Config{ReconnectDelay: time.Hour}) {
+		if err != nil {
+			t.Fatalf("Events() error = %v", err)
+		}
+
+		got = append(got, event.ID+":"+event.Data)
+		if len(got) == 2 {
+			break
+		}
+	}
+
+	if fmt.Sprint(got) != "[1:one 2:two]" {
+		t.Fatalf("Events() = %v, want [1:one 2:two]", got)
+	}
+
+	if id := resumed.Load(); id != "1" {
+		t.Fatalf("Last-Event-ID = %v, want 1", id)
+	}
+
+	// the headers were set on clones of req
+	unwrapped, err := req.Unwrap()
+	if err != nil {
+		t.Fatalf("Unwrap() error = %v", err)
+	}
+
+	for _, key := range []string{"Cache-Control", "Last-Event-ID"} {
+		if got := unwrapped.Header.Get(key); got != "" {
+			t.Fatalf("req %s = %q, want it untouched", key, got)
+		}
+	}
+}
+
+func TestEvents_Stops(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		wantErr error
+	}{
+		{
+			name:    "no content",
+			handler: func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusNoContent) },
+		},
+		{
+			name:    "not found",
+			handler: func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusNotFound) },
+			wantErr: ErrUnexpectedStatus,
+		},
+		{
+			name:    "not an event stream",
+			handler: func(w http.ResponseWriter, _ *http.Request) { _, _ = io.WriteString(w, "{}") },
+			wantErr: ErrUnexpectedContentType,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			server := httptest.NewServer(tt.handler)
+			t.Cleanup(server.Close)
+
+			var last error
+
+			for _, err := range Events(maigo.DefaultClient(server.URL).GET("/"), Config{}) {
+				last = err
+			}
+
+			if !errors.Is(last, tt.wantErr) || (tt.wantErr == nil && last != nil) {
+				t.Fatalf("Events() error = %v, want %v", last, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEvents_MaxReconnects(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(server.Close)
+
+	var delays []time.Duration
+
+	cfg := Config{
+		ReconnectDelay: time.Millisecond,
+		MaxReconnects:  3,
+		OnReconnect: func(_ int, err error, delay time.Duration) {
+			if !errors.Is(err, ErrUnexpectedStatus) {
+				t.Errorf("OnReconnect() error = %v, want %v", err, ErrUnexpectedStatus)
+			}
+
+			delays = append(delays, delay)
+		},
+	}
+
+	var last error
+	for _, err := range Events(maigo.DefaultClient(server.URL).GET("/"), cfg) {
+		last = err
+	}
+
+	if !errors.Is(last, ErrTooManyReconnects) {
+		t.Fatalf("Events() error = %v, want %v", last, ErrTooManyReconnects)
+	}
+
+	if fmt.Sprint(delays) != "[1ms 2ms 4ms]" {
+		t.Fatalf("delays = %v, want [1ms 2ms 4ms]", delays)
+	}
+}
+
+func TestSubscribe_ContextCancel(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeStream(w, "data: hello\n\n")
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	t.Cleanup(server.Close)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	events, errs := Subscribe(maigo.DefaultClient(server.URL).GET("/").Context().Set(ctx), Config{})
+
+	if event := <-events; event.Data != "hello" {
+		t.Fatalf("event = %+v, want data hello", event)
+	}
+
+	cancel()
+
+	for range events {
+		t.Fatalf("event received after cancel")
+	}
+
+	if err := <-errs; !errors.Is(err, context.Canceled) {
+		t.Fatalf("Subscribe() error = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestEvents_ExpectSuccessStopsOnClientError(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	t.Cleanup(server.Close)
+
+	client := maigo.NewClient(server.URL).ExpectSuccess().Build()
+
+	var last error
+	for _, err := range Events(client.GET("/"), Config{ReconnectDelay: time.Millisecond}) {
+		last = err
+	}
+
+	if !errors.Is(last, ErrUnexpectedStatus) {
+		t.Fatalf("Events() error = %v, want %v", last, ErrUnexpectedStatus)
+	}
+
+	if got := calls.Load(); got != 1 {
+		t.Fatalf("requests = %d, want 1", got)
+	}
+}
+
+func TestEvents_ResetLastEventID(t *testing.T) {
+	t.Parallel()
+
+	var (
+		hits  atomic.Int32
+		sent  []string
+		mutex sync.Mutex
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		sent = append(sent, strings.Join(r.Header.Values("Last-Event-ID"), ","))
+		mutex.Unlock()
+
+		switch hits.Add(1) {
+		case 1:
+			writeStream(w, "retry: 1\nid: 1\ndata: one\n\n")
+		case 2:
+			// an empty id resets the last event id
+			writeStream(w, "id\ndata: two\n\n")
+		default:
+			writeStream(w, "data: three\n\n")
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	req := maigo.DefaultClient(server.URL).GET("/").Context().Set(ctx)
+
+	received := 0
+
+	for _, err := range Events(req, Config{}) {
+		if err != nil {
+			t.Fatalf("Events() error = %v", err)
+		}
+
+		if received++; received == 3 {
+			break
+		}
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if fmt.Sprint(sent) != "[ 1 ]" {
+		t.Fatalf("Last-Event-ID sent = %q, want none, 1, none", sent)
+	}
+}